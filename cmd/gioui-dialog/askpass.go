@@ -0,0 +1,126 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"path/filepath"
+	"strings"
+
+	"github.com/gesellix/gioui-dialog/pkg/dialog"
+)
+
+// askpassName is the name of the binary that runs in askpass mode, e.g.
+// as a link to gioui-dialog.
+const askpassName = "gioui-askpass"
+
+// askpassArgs returns the arguments of the askpass mode, and whether the
+// binary runs in it: when it was invoked as gioui-askpass, or with
+// --askpass as the first argument.
+func askpassArgs(argv []string) ([]string, bool) {
+	if strings.TrimSuffix(filepath.Base(argv[0]), ".exe") == askpassName {
+		return argv[1:], true
+	}
+	if len(argv) > 1 && argv[1] == "--askpass" {
+		return argv[2:], true
+	}
+	return nil, false
+}
+
+// askpassKind is the kind of dialog that answers an askpass prompt.
+type askpassKind int
+
+const (
+	// askpassSecret asks for a password or passphrase, which is printed.
+	askpassSecret askpassKind = iota
+	// askpassText asks for text that isn't secret, e.g. a user name of git.
+	askpassText
+	// askpassYesNo asks a yes/no question, e.g. whether to trust a host
+	// key, and prints the answer.
+	askpassYesNo
+	// askpassConfirm asks a question that is only answered by the exit
+	// code, for SSH_ASKPASS_PROMPT=confirm.
+	askpassConfirm
+	// askpassNotice shows a notice, for SSH_ASKPASS_PROMPT=none.
+	askpassNotice
+)
+
+// classifyPrompt returns the kind of dialog for the prompt of ssh, sudo or
+// git, and the SSH_ASKPASS_PROMPT variable of ssh.
+func classifyPrompt(prompt, sshPrompt string) askpassKind {
+	switch sshPrompt {
+	case "confirm":
+		return askpassConfirm
+	case "none":
+		return askpassNotice
+	}
+	lower := strings.ToLower(prompt)
+	switch {
+	case strings.Contains(lower, "(yes/no"):
+		// e.g. "Are you sure you want to continue connecting (yes/no/[fingerprint])?"
+		return askpassYesNo
+	case strings.HasPrefix(lower, "username"):
+		// e.g. "Username for 'https://github.com': "
+		return askpassText
+	default:
+		return askpassSecret
+	}
+}
+
+// runAskpass shows the dialog for the prompt given as arguments, like
+// ssh-askpass, and writes the answer to stdout. getenv looks up
+// SSH_ASKPASS_PROMPT. The exit code is 0 if the user answered, and 1 if
+// the dialog was canceled, closed, timed out or failed, in which case
+// nothing is written.
+func runAskpass(ctx context.Context, args []string, getenv func(string) string, stdout io.Writer) int {
+	prompt := strings.TrimSpace(strings.Join(args, " "))
+	kind := classifyPrompt(prompt, getenv("SSH_ASKPASS_PROMPT"))
+	if prompt == "" && kind == askpassSecret {
+		prompt = "Enter the password"
+	}
+
+	var (
+		res dialog.Result
+		err error
+	)
+	switch kind {
+	case askpassSecret:
+		res, err = dialog.RunPassword(ctx, dialog.PasswordDialogOptions{Title: "Authentication", Label: prompt})
+	case askpassText:
+		res, err = dialog.RunInput(ctx, dialog.InputDialogOptions{Title: "Authentication", Label: prompt})
+	case askpassYesNo, askpassConfirm:
+		res, err = dialog.RunMessage(ctx, dialog.SeverityQuestion, dialog.MessageDialogOptions{Title: "Confirmation", Message: prompt})
+	case askpassNotice:
+		res, err = dialog.RunMessage(ctx, dialog.SeverityInfo, dialog.MessageDialogOptions{Title: "Notice", Message: prompt})
+	}
+	return writeAskpass(stdout, kind, res, err)
+}
+
+// writeAskpass writes the answer of an askpass dialog of the given kind to
+// stdout, and returns the exit code.
+func writeAskpass(stdout io.Writer, kind askpassKind, res dialog.Result, err error) int {
+	// No is an answer to yes/no prompts, but closing the dialog isn't.
+	if kind == askpassYesNo && errors.Is(err, dialog.ErrCanceled) {
+		fmt.Fprintln(stdout, "no")
+		return exitOK
+	}
+	if err != nil {
+		if res.Outcome == dialog.Error {
+			log.Println("Error showing dialog:", err)
+		}
+		return exitCancel
+	}
+	switch kind {
+	case askpassSecret:
+		stdout.Write(res.Secret)
+		io.WriteString(stdout, "\n")
+		clear(res.Secret)
+	case askpassText:
+		fmt.Fprintln(stdout, res.Value)
+	case askpassYesNo:
+		fmt.Fprintln(stdout, "yes")
+	}
+	return exitOK
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gesellix/gioui-dialog/pkg/dialog"
+)
+
+func TestAskpassArgs(t *testing.T) {
+	tests := []struct {
+		argv []string
+		want []string
+		ok   bool
+	}{
+		{[]string{"gioui-askpass", "Password:"}, []string{"Password:"}, true},
+		{[]string{"/usr/local/bin/gioui-askpass"}, []string{}, true},
+		{[]string{"gioui-askpass.exe", "Passphrase:"}, []string{"Passphrase:"}, true},
+		{[]string{"gioui-dialog", "--askpass", "[sudo] password for alice: "}, []string{"[sudo] password for alice: "}, true},
+		{[]string{"gioui-dialog", "--entry", "--askpass"}, nil, false},
+		{[]string{"gioui-dialog"}, nil, false},
+		{[]string{"gioui-askpass-old", "x"}, nil, false},
+	}
+	for _, tt := range tests {
+		got, ok := askpassArgs(tt.argv)
+		if ok != tt.ok || ok && !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("askpassArgs(%q) = %q, %t, want %q, %t", tt.argv, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestClassifyPrompt(t *testing.T) {
+	tests := []struct {
+		prompt, sshPrompt string
+		want              askpassKind
+	}{
+		{"Enter passphrase for key '/home/alice/.ssh/id_ed25519': ", "", askpassSecret},
+		{"[sudo] password for alice: ", "", askpassSecret},
+		{"Password for 'https://alice@github.com': ", "", askpassSecret},
+		{"", "", askpassSecret},
+		{"Username for 'https://github.com': ", "", askpassText},
+		{"The authenticity of host 'example.org' can't be established.\n" +
+			"ED25519 key fingerprint is SHA256:abc.\n" +
+			"Are you sure you want to continue connecting (yes/no/[fingerprint])? ", "", askpassYesNo},
+		{"Allow use of key? (Yes/No)", "", askpassYesNo},
+		{"Allow use of key /home/alice/.ssh/id_ecdsa_sk?", "confirm", askpassConfirm},
+		{"Confirm user presence for key ECDSA-SK SHA256:abc", "none", askpassNotice},
+		{"Enter PIN for ECDSA-SK key: ", "", askpassSecret},
+	}
+	for _, tt := range tests {
+		if got := classifyPrompt(tt.prompt, tt.sshPrompt); got != tt.want {
+			t.Errorf("classifyPrompt(%q, %q) = %d, want %d", tt.prompt, tt.sshPrompt, got, tt.want)
+		}
+	}
+}
+
+func TestWriteAskpass(t *testing.T) {
+	tests := []struct {
+		name   string
+		kind   askpassKind
+		res    dialog.Result
+		err    error
+		code   int
+		stdout string
+	}{
+		{"secret", askpassSecret, dialog.Result{Outcome: dialog.Confirmed, Secret: []byte("s3cret")}, nil, 0, "s3cret\n"},
+		{"secret canceled", askpassSecret, dialog.Result{Outcome: dialog.Canceled}, dialog.ErrCanceled, 1, ""},
+		{"secret closed", askpassSecret, dialog.Result{Outcome: dialog.Dismissed}, dialog.ErrDismissed, 1, ""},
+		{"secret timed out", askpassSecret, dialog.Result{Outcome: dialog.TimedOut}, dialog.ErrTimeout, 1, ""},
+		{"secret failed", askpassSecret, dialog.Result{Outcome: dialog.Error, Err: errors.New("no display")}, errors.New("no display"), 1, ""},
+		{"text", askpassText, dialog.Result{Outcome: dialog.Confirmed, Value: "alice"}, nil, 0, "alice\n"},
+		{"yes", askpassYesNo, dialog.Result{Outcome: dialog.Confirmed}, nil, 0, "yes\n"},
+		{"no", askpassYesNo, dialog.Result{Outcome: dialog.Canceled}, dialog.ErrCanceled, 0, "no\n"},
+		{"yes/no closed", askpassYesNo, dialog.Result{Outcome: dialog.Dismissed}, dialog.ErrDismissed, 1, ""},
+		{"confirm yes", askpassConfirm, dialog.Result{Outcome: dialog.Confirmed}, nil, 0, ""},
+		{"confirm no", askpassConfirm, dialog.Result{Outcome: dialog.Canceled}, dialog.ErrCanceled, 1, ""},
+		{"notice", askpassNotice, dialog.Result{Outcome: dialog.Confirmed}, nil, 0, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout strings.Builder
+			if code := writeAskpass(&stdout, tt.kind, tt.res, tt.err); code != tt.code {
+				t.Errorf("exit code %d, want %d", code, tt.code)
+			}
+			if stdout.String() != tt.stdout {
+				t.Errorf("stdout %q, want %q", stdout.String(), tt.stdout)
+			}
+		})
+	}
+}
+
+func TestWriteAskpassClearsSecret(t *testing.T) {
+	secret := []byte("s3cret")
+	writeAskpass(&strings.Builder{}, askpassSecret, dialog.Result{Outcome: dialog.Confirmed, Secret: secret}, nil)
+	if string(secret) != "\x00\x00\x00\x00\x00\x00" {
+		t.Errorf("secret not cleared: %q", secret)
+	}
+}
@@ -0,0 +1,377 @@
+package main
+
+import (
+	"bufio"
+	"cmp"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gesellix/gioui-dialog/pkg/dialog"
+)
+
+// Exit codes, compatible with zenity.
+const (
+	exitOK      = 0
+	exitCancel  = 1
+	exitTimeout = 5
+	exitError   = -1
+)
+
+// cliOptions holds the zenity-compatible command-line options.
+type cliOptions struct {
+	// Dialog types, of which at most one may be given
+	entry, list, question, info, errorMsg, warning, password, progress bool
+
+	// General options
+	title, text   string
+	width, height int
+	timeout       int
+	okLabel       string
+	cancelLabel   string
+	output        string
+
+	// Entry options
+	entryText string
+	hideText  bool
+
+	// List options
+	columns   stringsFlag
+	checklist bool
+	multiple  bool
+	separator string
+
+	// Progress options
+	percentage float64
+	pulsate    bool
+	autoClose  bool
+}
+
+// stringsFlag is a flag that can be given several times, e.g. --column.
+type stringsFlag []string
+
+func (s *stringsFlag) String() string { return strings.Join(*s, ",") }
+
+func (s *stringsFlag) Set(value string) error {
+	*s = append(*s, value)
+	return nil
+}
+
+// parseArgs parses the command-line arguments, and returns the options and
+// the remaining arguments, e.g. the rows of a list.
+func parseArgs(args []string, output io.Writer) (*cliOptions, []string, error) {
+	o := &cliOptions{}
+	fs := flag.NewFlagSet("gioui-dialog", flag.ContinueOnError)
+	fs.SetOutput(output)
+
+	fs.BoolVar(&o.entry, "entry", false, "display a text entry dialog")
+	fs.BoolVar(&o.list, "list", false, "display a list dialog of the arguments, or of the lines of stdin")
+	fs.BoolVar(&o.question, "question", false, "display a question dialog")
+	fs.BoolVar(&o.info, "info", false, "display an info dialog")
+	fs.BoolVar(&o.errorMsg, "error", false, "display an error dialog")
+	fs.BoolVar(&o.warning, "warning", false, "display a warning dialog")
+	fs.BoolVar(&o.password, "password", false, "display a password dialog")
+	fs.BoolVar(&o.progress, "progress", false, "display a progress dialog updated from stdin")
+
+	fs.StringVar(&o.title, "title", "", "window title")
+	fs.StringVar(&o.text, "text", "", "dialog text")
+	fs.IntVar(&o.width, "width", 0, "window width")
+	fs.IntVar(&o.height, "height", 0, "window height")
+	fs.IntVar(&o.timeout, "timeout", 0, "close the dialog after the given number of seconds")
+	fs.StringVar(&o.okLabel, "ok-label", "", "label of the OK button")
+	fs.StringVar(&o.cancelLabel, "cancel-label", "", "label of the Cancel button")
+	fs.StringVar(&o.output, "output", "text", "format of the result: text, or json for an object with the outcome")
+
+	fs.StringVar(&o.entryText, "entry-text", "", "initial text of the entry")
+	fs.BoolVar(&o.hideText, "hide-text", false, "hide the text of the entry")
+
+	fs.Var(&o.columns, "column", "column header of the list; repeat it for several columns")
+	fs.BoolVar(&o.checklist, "checklist", false, "start the rows of the list with TRUE or FALSE, and check several of them")
+	fs.BoolVar(&o.multiple, "multiple", false, "allow selecting several rows of the list")
+	fs.StringVar(&o.separator, "separator", "|", "separator of the selected rows of the list")
+
+	fs.Float64Var(&o.percentage, "percentage", 0, "initial percentage of the progress dialog")
+	fs.BoolVar(&o.pulsate, "pulsate", false, "pulsate the progress bar until a percentage is read")
+	fs.BoolVar(&o.autoClose, "auto-close", false, "close the progress dialog when 100% is reached")
+
+	if err := fs.Parse(args); err != nil {
+		return nil, nil, err
+	}
+	if o.output != "text" && o.output != "json" {
+		err := fmt.Errorf("invalid value %q for --output: use text or json", o.output)
+		fmt.Fprintln(output, err)
+		return nil, nil, err
+	}
+	return o, fs.Args(), nil
+}
+
+// outputFormat returns the value of the last --output flag in args, or
+// "text". Unlike parseArgs, it doesn't stop at invalid flags, so that their
+// errors can be reported in the requested format.
+func outputFormat(args []string) string {
+	format := "text"
+	for i := 0; i < len(args) && args[i] != "--"; i++ {
+		name, value, hasValue := strings.Cut(strings.TrimLeft(args[i], "-"), "=")
+		if !strings.HasPrefix(args[i], "-") || name != "output" {
+			continue
+		}
+		if !hasValue && i+1 < len(args) {
+			i++
+			value = args[i]
+		}
+		format = value
+	}
+	return format
+}
+
+// mode returns the name of the dialog type given on the command line, or ""
+// if there is none.
+func (o *cliOptions) mode() (string, error) {
+	modes := []struct {
+		name string
+		set  bool
+	}{
+		{"entry", o.entry},
+		{"list", o.list},
+		{"question", o.question},
+		{"info", o.info},
+		{"error", o.errorMsg},
+		{"warning", o.warning},
+		{"password", o.password},
+		{"progress", o.progress},
+	}
+	mode := ""
+	for _, m := range modes {
+		if !m.set {
+			continue
+		}
+		if mode != "" {
+			return "", fmt.Errorf("only one dialog type may be given, got --%s and --%s", mode, m.name)
+		}
+		mode = m.name
+	}
+	return mode, nil
+}
+
+// runCLI shows the dialog of the given mode, writes its result to stdout
+// and returns the exit code. With --output=json, the result is written
+// however the dialog was closed.
+func runCLI(o *cliOptions, mode string, args []string, stdin io.Reader, stdout io.Writer) int {
+	start := time.Now()
+	res, err := runMode(context.Background(), o, mode, args, stdin)
+	if o.output == "json" {
+		writeJSON(stdout, res, err, mode == "list" && o.multiSelect(), time.Since(start))
+	} else if err == nil {
+		writeResult(stdout, o, mode, res)
+	}
+	return exitCode(err)
+}
+
+// runMode shows the dialog of the given mode and returns its result.
+func runMode(ctx context.Context, o *cliOptions, mode string, args []string, stdin io.Reader) (dialog.Result, error) {
+	switch mode {
+	case "entry":
+		if o.hideText {
+			return runPassword(ctx, o)
+		}
+		return dialog.RunInput(ctx, dialog.InputDialogOptions{
+			Width: float32(o.width), Height: float32(o.height),
+			Title:       o.title,
+			Label:       o.text,
+			DefaultText: o.entryText,
+			Buttons:     o.buttons("OK", "Cancel"),
+			Timeout:     o.timeoutDuration(),
+		})
+	case "password":
+		return runPassword(ctx, o)
+	case "list":
+		return runList(ctx, o, args, stdin)
+	case "question":
+		return dialog.RunMessage(ctx, dialog.SeverityQuestion, o.messageOptions(o.buttons("Yes", "No")))
+	case "info", "error", "warning":
+		var buttons []dialog.Button
+		if o.okLabel != "" {
+			buttons = []dialog.Button{{Label: o.okLabel, Role: dialog.RoleAffirmative, Default: true}}
+		}
+		severity := map[string]dialog.Severity{
+			"info":    dialog.SeverityInfo,
+			"error":   dialog.SeverityError,
+			"warning": dialog.SeverityWarning,
+		}[mode]
+		return dialog.RunMessage(ctx, severity, o.messageOptions(buttons))
+	case "progress":
+		return runProgress(ctx, stdin, dialog.ProgressDialogOptions{
+			Width: float32(o.width), Height: float32(o.height),
+			Title:     o.title,
+			Label:     o.text,
+			Percent:   o.percentage,
+			Pulsate:   o.pulsate,
+			AutoClose: o.autoClose,
+			Buttons:   o.buttons("OK", "Cancel"),
+			Timeout:   o.timeoutDuration(),
+		})
+	default:
+		return dialog.Result{}, fmt.Errorf("unknown dialog type %q", mode)
+	}
+}
+
+// timeoutDuration returns the duration of --timeout.
+func (o *cliOptions) timeoutDuration() time.Duration {
+	return time.Duration(o.timeout) * time.Second
+}
+
+// buttons returns cancel and OK buttons with the labels of --cancel-label
+// and --ok-label, or nil for the default buttons of the dialog. ok and
+// cancel are the default labels.
+func (o *cliOptions) buttons(ok, cancel string) []dialog.Button {
+	if o.okLabel == "" && o.cancelLabel == "" {
+		return nil
+	}
+	return []dialog.Button{
+		{Label: cmp.Or(o.cancelLabel, cancel), Role: dialog.RoleNegative, Cancel: true},
+		{Label: cmp.Or(o.okLabel, ok), Role: dialog.RoleAffirmative, Default: true},
+	}
+}
+
+func (o *cliOptions) messageOptions(buttons []dialog.Button) dialog.MessageDialogOptions {
+	return dialog.MessageDialogOptions{
+		Width: float32(o.width), Height: float32(o.height),
+		Title:   o.title,
+		Message: o.text,
+		Buttons: buttons,
+		Timeout: o.timeoutDuration(),
+	}
+}
+
+func runPassword(ctx context.Context, o *cliOptions) (dialog.Result, error) {
+	return dialog.RunPassword(ctx, dialog.PasswordDialogOptions{
+		Width: float32(o.width), Height: float32(o.height),
+		Title:   o.title,
+		Label:   cmp.Or(o.text, "Enter the password"),
+		Buttons: o.buttons("OK", "Cancel"),
+		Timeout: o.timeoutDuration(),
+	})
+}
+
+// runList shows the rows given as arguments, or read from stdin, and
+// returns the first column of the selected rows. With several columns,
+// the values are distributed over the rows in order. With --checklist,
+// every row starts with TRUE or FALSE, which checks it initially, and the
+// column after it is returned.
+func runList(ctx context.Context, o *cliOptions, args []string, stdin io.Reader) (dialog.Result, error) {
+	values := args
+	if len(values) == 0 {
+		var err error
+		if values, err = readLines(stdin); err != nil {
+			return dialog.Result{}, err
+		}
+	}
+
+	choices, checked, first := listChoices(o, values)
+	if !o.multiSelect() {
+		res, err := dialog.RunSelect(ctx, dialog.SelectDialogOptions{
+			Width: float32(o.width), Height: float32(o.height),
+			Title:   o.title,
+			Label:   o.text,
+			Choices: choices,
+			Buttons: o.buttons("OK", "Cancel"),
+			Timeout: o.timeoutDuration(),
+		})
+		res.Value = first[res.Value]
+		return res, err
+	}
+	res, err := dialog.RunMultiSelect(ctx, dialog.MultiSelectDialogOptions{
+		Width: float32(o.width), Height: float32(o.height),
+		Title:             o.title,
+		Label:             o.text,
+		Choices:           choices,
+		DefaultSelections: checked,
+		Buttons:           o.buttons("OK", "Cancel"),
+		Timeout:           o.timeoutDuration(),
+	})
+	for i, v := range res.Values {
+		res.Values[i] = first[v]
+	}
+	return res, err
+}
+
+// listChoices returns the choices of a list of the given values, the ones
+// that are checked initially, and a map of the choices to their first
+// column.
+func listChoices(o *cliOptions, values []string) (choices, checked []string, first map[string]string) {
+	first = make(map[string]string)
+	for row := range slices.Chunk(values, max(len(o.columns), 1)) {
+		isChecked := false
+		if o.checklist {
+			isChecked, row = strings.EqualFold(row[0], "TRUE"), row[1:]
+		}
+		if len(row) == 0 {
+			continue
+		}
+		choice := strings.Join(row, "  ")
+		if _, ok := first[choice]; ok {
+			continue
+		}
+		first[choice] = row[0]
+		choices = append(choices, choice)
+		if isChecked {
+			checked = append(checked, choice)
+		}
+	}
+	return choices, checked, first
+}
+
+// multiSelect reports whether several rows of a list can be selected.
+func (o *cliOptions) multiSelect() bool {
+	return o.checklist || o.multiple
+}
+
+// readLines returns the lines read from r.
+func readLines(r io.Reader) ([]string, error) {
+	var lines []string
+	s := bufio.NewScanner(r)
+	for s.Scan() {
+		lines = append(lines, s.Text())
+	}
+	return lines, s.Err()
+}
+
+// writeResult writes the result of a confirmed dialog to w, like zenity:
+// the entered text or password, or the selected rows of a list.
+func writeResult(w io.Writer, o *cliOptions, mode string, res dialog.Result) {
+	switch {
+	case res.Secret != nil:
+		w.Write(res.Secret)
+		io.WriteString(w, "\n")
+		clear(res.Secret)
+	case mode == "entry":
+		fmt.Fprintln(w, res.Value)
+	case mode == "list" && o.multiSelect():
+		fmt.Fprintln(w, strings.Join(res.Values, o.separator))
+	case mode == "list":
+		fmt.Fprintln(w, res.Value)
+	}
+}
+
+// exitCode returns the exit code for the error of a dialog: 0 if the user
+// confirmed it, 1 if it was canceled or closed, 5 if it timed out, and -1
+// if it failed, in which case the error is logged.
+func exitCode(err error) int {
+	switch {
+	case err == nil:
+		return exitOK
+	case errors.Is(err, dialog.ErrCanceled), errors.Is(err, dialog.ErrDismissed):
+		return exitCancel
+	case errors.Is(err, dialog.ErrTimeout):
+		return exitTimeout
+	default:
+		log.Println("Error showing dialog:", err)
+		return exitError
+	}
+}
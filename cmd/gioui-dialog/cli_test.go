@@ -0,0 +1,183 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gesellix/gioui-dialog/pkg/dialog"
+)
+
+func TestParseArgs(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		mode string
+		rest []string
+		err  string // substring of the error, or "" if valid
+	}{
+		{"demo", nil, "", nil, ""},
+		{"entry", []string{"--entry", "--title", "Name", "--entry-text=Alice"}, "entry", []string{}, ""},
+		{"single dash", []string{"-question", "-text", "Continue?"}, "question", []string{}, ""},
+		{"list rows", []string{"--list", "--column", "Name", "--column", "Size", "a", "1", "b", "2"}, "list", []string{"a", "1", "b", "2"}, ""},
+		{"error dialog", []string{"--error", "--output", "json"}, "error", []string{}, ""},
+		{"unknown flag", []string{"--calendar"}, "", nil, "flag provided but not defined: -calendar"},
+		{"missing value", []string{"--entry", "--title"}, "", nil, "flag needs an argument: -title"},
+		{"invalid number", []string{"--progress", "--percentage", "half"}, "", nil, `invalid value "half"`},
+		{"invalid output", []string{"--entry", "--output", "xml"}, "", nil, `invalid value "xml" for --output`},
+		{"conflicting modes", []string{"--entry", "--password"}, "", nil, "only one dialog type may be given, got --entry and --password"},
+		{"conflicting messages", []string{"--info", "--warning", "--error"}, "", nil, "got --info and --error"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o, rest, err := parseArgs(tt.args, io.Discard)
+			mode := ""
+			if err == nil {
+				mode, err = o.mode()
+			}
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Errorf("got error %v, want %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if mode != tt.mode || !reflect.DeepEqual(rest, tt.rest) {
+				t.Errorf("got mode %q, args %q, want %q, %q", mode, rest, tt.mode, tt.rest)
+			}
+		})
+	}
+}
+
+func TestParseArgsOptions(t *testing.T) {
+	o, _, err := parseArgs([]string{
+		"--list", "--checklist", "--multiple", "--separator=,", "--column=Pick", "--column=Name",
+		"--width=300", "--height=200", "--timeout=10", "--ok-label=Go", "--cancel-label=Stop",
+	}, io.Discard)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &cliOptions{
+		list: true, checklist: true, multiple: true, separator: ",", columns: stringsFlag{"Pick", "Name"},
+		width: 300, height: 200, timeout: 10, okLabel: "Go", cancelLabel: "Stop", output: "text",
+	}
+	if !reflect.DeepEqual(o, want) {
+		t.Errorf("got %+v, want %+v", o, want)
+	}
+}
+
+func TestListChoices(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		values  []string
+		multi   bool
+		choices []string
+		checked []string
+		first   map[string]string
+	}{
+		{
+			name:    "rows",
+			args:    []string{"--list"},
+			values:  []string{"a", "b", "a"},
+			choices: []string{"a", "b"},
+			first:   map[string]string{"a": "a", "b": "b"},
+		},
+		{
+			name:    "columns",
+			args:    []string{"--list", "--column=Name", "--column=Size"},
+			values:  []string{"a", "1", "b", "2", "c"},
+			choices: []string{"a  1", "b  2", "c"},
+			first:   map[string]string{"a  1": "a", "b  2": "b", "c": "c"},
+		},
+		{
+			name:    "multiple",
+			args:    []string{"--list", "--multiple"},
+			values:  []string{"a", "b"},
+			multi:   true,
+			choices: []string{"a", "b"},
+			first:   map[string]string{"a": "a", "b": "b"},
+		},
+		{
+			name:    "checklist",
+			args:    []string{"--list", "--checklist", "--column=Pick", "--column=Name", "--column=Size"},
+			values:  []string{"TRUE", "a", "1", "false", "b", "2", "true", "c", "3", "TRUE"},
+			multi:   true,
+			choices: []string{"a  1", "b  2", "c  3"},
+			checked: []string{"a  1", "c  3"},
+			first:   map[string]string{"a  1": "a", "b  2": "b", "c  3": "c"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o, _, err := parseArgs(tt.args, io.Discard)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if o.multiSelect() != tt.multi {
+				t.Errorf("multiSelect() = %t, want %t", o.multiSelect(), tt.multi)
+			}
+			choices, checked, first := listChoices(o, tt.values)
+			if !reflect.DeepEqual(choices, tt.choices) || !reflect.DeepEqual(checked, tt.checked) || !reflect.DeepEqual(first, tt.first) {
+				t.Errorf("got %q, %q, %q, want %q, %q, %q", choices, checked, first, tt.choices, tt.checked, tt.first)
+			}
+		})
+	}
+}
+
+func TestWriteResult(t *testing.T) {
+	tests := []struct {
+		args []string
+		res  dialog.Result
+		want string
+	}{
+		{[]string{"--entry"}, dialog.Result{Value: "Alice"}, "Alice\n"},
+		{[]string{"--password"}, dialog.Result{Secret: []byte("s3cret")}, "s3cret\n"},
+		{[]string{"--list"}, dialog.Result{Value: "b"}, "b\n"},
+		{[]string{"--list", "--checklist"}, dialog.Result{Values: []string{"a", "c"}}, "a|c\n"},
+		{[]string{"--list", "--multiple", "--separator", "\t"}, dialog.Result{Values: []string{"a", "c"}}, "a\tc\n"},
+		{[]string{"--question"}, dialog.Result{}, ""},
+	}
+	for _, tt := range tests {
+		o, _, err := parseArgs(tt.args, io.Discard)
+		if err != nil {
+			t.Fatal(err)
+		}
+		mode, _ := o.mode()
+		var out strings.Builder
+		writeResult(&out, o, mode, tt.res)
+		if out.String() != tt.want {
+			t.Errorf("%q: got %q, want %q", tt.args, out.String(), tt.want)
+		}
+	}
+}
+
+func TestExitCode(t *testing.T) {
+	// Failures are logged
+	defer log.SetOutput(log.Writer())
+	log.SetOutput(io.Discard)
+
+	tests := []struct {
+		outcome dialog.Outcome
+		err     error
+		want    int
+	}{
+		{dialog.Confirmed, nil, 0},
+		{dialog.Canceled, dialog.ErrCanceled, 1},
+		{dialog.Dismissed, dialog.ErrDismissed, 1},
+		{dialog.TimedOut, dialog.ErrTimeout, 5},
+		{dialog.TimedOut, fmt.Errorf("list: %w", dialog.ErrTimeout), 5},
+		{dialog.Error, errors.New("no display"), -1},
+	}
+	for _, tt := range tests {
+		if got := exitCode(tt.err); got != tt.want {
+			t.Errorf("%s: exitCode(%v) = %d, want %d", tt.outcome, tt.err, got, tt.want)
+		}
+	}
+}
@@ -1,9 +1,13 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"flag"
 	"fmt"
 	"log"
 	"os"
+	"time"
 
 	"gioui.org/app"
 	"gioui.org/layout"
@@ -14,7 +18,54 @@ import (
 )
 
 // Gio-based demo UI showcasing the dialog API usage on desktop platforms.
+// Given a dialog type like --entry or --question, it shows that dialog
+// instead, compatible with zenity. The run, validate and schema commands
+// work with dialogs described in JSON or YAML files, and the serve command
+// shows the dialogs requested over JSON-RPC. Invoked as gioui-askpass or
+// with --askpass, it is a graphical askpass helper for ssh, sudo and git.
 func main() {
+	if args, ok := askpassArgs(os.Args); ok {
+		go func() {
+			os.Exit(runAskpass(context.Background(), args, os.Getenv, os.Stdout))
+		}()
+		app.Main()
+	}
+
+	if len(os.Args) > 1 && (specCommands[os.Args[1]] || os.Args[1] == "serve") {
+		go func() {
+			if os.Args[1] == "serve" {
+				os.Exit(runServe(os.Args[2:], os.Stdin, os.Stdout, os.Stderr))
+			}
+			os.Exit(runSpecCommand(os.Args[1], os.Args[2:], os.Stdin, os.Stdout, os.Stderr))
+		}()
+		app.Main()
+	}
+
+	opts, args, err := parseArgs(os.Args[1:], os.Stderr)
+	if errors.Is(err, flag.ErrHelp) {
+		os.Exit(exitOK)
+	}
+	if err != nil {
+		if outputFormat(os.Args[1:]) == "json" {
+			writeJSON(os.Stdout, dialog.Result{}, err, false, 0)
+		}
+		os.Exit(exitError)
+	}
+	mode, err := opts.mode()
+	if err != nil {
+		if opts.output == "json" {
+			writeJSON(os.Stdout, dialog.Result{}, err, false, 0)
+		}
+		os.Exit(exitCode(err))
+	}
+
+	if mode != "" {
+		go func() {
+			os.Exit(runCLI(opts, mode, args, os.Stdin, os.Stdout))
+		}()
+		app.Main()
+	}
+
 	// Run the Gio application in a separate goroutine and exit on close.
 	go func() {
 		w := new(app.Window)
@@ -34,10 +85,20 @@ func eventLoop(w *app.Window) error {
 
 	// Clickable buttons and result display state.
 	var (
-		inputBtn   widget.Clickable
-		selectBtn  widget.Clickable
-		baseBtn    widget.Clickable
-		resultText string
+		inputBtn       widget.Clickable
+		passwordBtn    widget.Clickable
+		selectBtn      widget.Clickable
+		multiSelectBtn widget.Clickable
+		openFileBtn    widget.Clickable
+		saveFileBtn    widget.Clickable
+		directoryBtn   widget.Clickable
+		progressBtn    widget.Clickable
+		formBtn        widget.Clickable
+		baseBtn        widget.Clickable
+		questionBtn    widget.Clickable
+		modalBtn       widget.Clickable
+		modal          *dialog.Modal
+		resultText     string
 	)
 
 	for {
@@ -68,6 +129,26 @@ func eventLoop(w *app.Window) error {
 				}()
 			}
 
+			if passwordBtn.Clicked(gtx) {
+				go func() {
+					secret, canceled, err := dialog.PromptPassword(dialog.PasswordDialogOptions{
+						Title:        "Password",
+						Label:        "Choose a password",
+						Description:  "Enter the new password twice.",
+						Confirmation: true,
+					})
+					defer clear(secret)
+					if err != nil {
+						log.Println("Error showing password dialog:", err)
+						resultText = "Error"
+					} else if canceled {
+						resultText = "Canceled"
+					} else {
+						resultText = fmt.Sprintf("Password with %d bytes entered", len(secret))
+					}
+				}()
+			}
+
 			if selectBtn.Clicked(gtx) {
 				go func() {
 					choice, canceled, err := dialog.PromptSelect(dialog.SelectDialogOptions{
@@ -77,6 +158,8 @@ func eventLoop(w *app.Window) error {
 						Choices:          []string{"Option A", "Option B", "Option C", "Option D", "Option E", "Option F", "Option G", "Option H"},
 						DefaultSelection: "Option B",
 						AllowCustomEntry: true,
+						Searchable:       true,
+						SearchMode:       dialog.MatchFuzzy,
 					})
 					if err != nil {
 						log.Println("Error showing select dialog:", err)
@@ -89,41 +172,255 @@ func eventLoop(w *app.Window) error {
 				}()
 			}
 
-			if baseBtn.Clicked(gtx) {
+			if multiSelectBtn.Clicked(gtx) {
 				go func() {
-					confirmed, canceled, err := dialog.PromptBase(dialog.BaseDialogOptions{
-						Title:       "Base Dialog",
-						Label:       "This is a base dialog",
-						Description: "Shows only title, label, description and OK/Cancel buttons.",
+					choices, canceled, err := dialog.PromptMultiSelect(dialog.MultiSelectDialogOptions{
+						Title:             "Multiple Selection",
+						Label:             "Choose toppings",
+						Description:       "Select one to three items from the list",
+						Choices:           []string{"Cheese", "Tomatoes", "Mushrooms", "Onions", "Peppers", "Olives", "Ham", "Pineapple"},
+						DefaultSelections: []string{"Cheese"},
+						MinSelections:     1,
+						MaxSelections:     3,
 					})
 					if err != nil {
-						log.Println("Error showing base dialog:", err)
+						log.Println("Error showing multi-select dialog:", err)
+						resultText = "Error"
+					} else if canceled {
+						resultText = "Canceled"
+					} else {
+						resultText = fmt.Sprintf("Selection: %q", choices)
+					}
+				}()
+			}
+
+			if openFileBtn.Clicked(gtx) {
+				go func() {
+					paths, canceled, err := dialog.PromptOpenFile(dialog.OpenFileDialogOptions{
+						Title: "Open Files",
+						Label: "Choose files to open",
+						Filters: []dialog.FileFilter{
+							{Name: "Go files", Patterns: []string{"*.go"}},
+							{Name: "All files", Patterns: []string{"*"}},
+						},
+						Multiple: true,
+					})
+					if err != nil {
+						log.Println("Error showing open file dialog:", err)
 						resultText = "Error"
 					} else if canceled {
+						resultText = "Canceled"
+					} else {
+						resultText = fmt.Sprintf("Files: %q", paths)
+					}
+				}()
+			}
+
+			if saveFileBtn.Clicked(gtx) {
+				go func() {
+					path, canceled, err := dialog.PromptSaveFile(dialog.SaveFileDialogOptions{
+						Title: "Save File",
+						Label: "Save the notes as",
+						Name:  "notes.txt",
+						Filters: []dialog.FileFilter{
+							{Name: "Text files", Patterns: []string{"*.txt"}},
+						},
+					})
+					if err != nil {
+						log.Println("Error showing save file dialog:", err)
+						resultText = "Error"
+					} else if canceled {
+						resultText = "Canceled"
+					} else {
+						resultText = "Save as: " + path
+					}
+				}()
+			}
+
+			if directoryBtn.Clicked(gtx) {
+				go func() {
+					path, canceled, err := dialog.PromptDirectory(dialog.DirectoryDialogOptions{
+						Title:          "Select Folder",
+						Label:          "Choose the output folder",
+						MustBeWritable: true,
+					})
+					if err != nil {
+						log.Println("Error showing directory dialog:", err)
+						resultText = "Error"
+					} else if canceled {
+						resultText = "Canceled"
+					} else {
+						resultText = "Folder: " + path
+					}
+				}()
+			}
+
+			if progressBtn.Clicked(gtx) {
+				go func() {
+					p, ctx := dialog.StartProgress(context.Background(), dialog.ProgressDialogOptions{
+						Title:   "Progress",
+						Label:   "Copying files",
+						Pulsate: true,
+						Text:    "Counting files...",
+					})
+					time.Sleep(time.Second)
+					for i := 1; i <= 20 && ctx.Err() == nil; i++ {
+						p.SetPercent(float64(i * 5))
+						p.SetText(fmt.Sprintf("Copying file %d of 20", i))
+						time.Sleep(150 * time.Millisecond)
+					}
+					p.Complete()
+					if _, err := p.Wait(); err != nil {
+						resultText = "Progress: " + err.Error()
+					} else {
+						resultText = "Progress: done"
+					}
+				}()
+			}
+
+			if formBtn.Clicked(gtx) {
+				go func() {
+					login := struct {
+						Host     string `form:"host"`
+						Port     int    `form:"port"`
+						User     string `form:"user"`
+						Password []byte `form:"password"`
+						Protocol string `form:"protocol,choices=SSH|SFTP|FTP"`
+						Remember bool   `form:"remember,label=Remember me"`
+					}{Host: "localhost", Port: 22}
+					defer clear(login.Password)
+					canceled, err := dialog.PromptFormStruct(dialog.FormDialogOptions{
+						Title: "Connect",
+						Label: "Connect to a server",
+					}, &login)
+					if err != nil {
+						log.Println("Error showing form dialog:", err)
+						resultText = "Error"
+					} else if canceled {
+						resultText = "Canceled"
+					} else {
+						resultText = fmt.Sprintf("Connect to %s@%s:%d via %s", login.User, login.Host, login.Port, login.Protocol)
+					}
+				}()
+			}
+
+			if baseBtn.Clicked(gtx) {
+				go func() {
+					res, err := dialog.RunBase(context.Background(), dialog.BaseDialogOptions{
+						Title:       "Base Dialog",
+						Label:       "Save changes?",
+						Description: "Shows only title, label, description and a custom button set.",
+						Buttons: []dialog.Button{
+							{Label: "Cancel", Role: dialog.RoleNegative, Cancel: true},
+							{Label: "Discard", Role: dialog.RoleDestructive},
+							{Label: "Save", Role: dialog.RoleAffirmative, Default: true},
+						},
+					})
+					switch {
+					case err == nil:
+						resultText = "Base dialog: " + res.Button.Label
+					case errors.Is(err, dialog.ErrCanceled):
 						resultText = "Base dialog canceled"
-					} else if confirmed {
-						resultText = "Base dialog confirmed"
+					case errors.Is(err, dialog.ErrDismissed):
+						resultText = "Base dialog closed"
+					default:
+						log.Println("Error showing base dialog:", err)
+						resultText = "Error"
 					}
 				}()
 			}
 
+			if questionBtn.Clicked(gtx) {
+				go func() {
+					yes, err := dialog.AskQuestion(dialog.MessageDialogOptions{
+						Title:   "Question",
+						Label:   "Show a warning?",
+						Message: "Answer Yes to see a warning message next.",
+					})
+					if err != nil {
+						log.Println("Error showing question dialog:", err)
+						resultText = "Error"
+						return
+					}
+					resultText = fmt.Sprintf("Answer: yes=%t", yes)
+					if yes {
+						err = dialog.ShowWarning(dialog.MessageDialogOptions{
+							Title:   "Warning",
+							Label:   "Disk almost full",
+							Message: "Only 1.2 GB are left on /dev/sda1. The message can be selected and copied.",
+						})
+						if err != nil {
+							log.Println("Error showing warning dialog:", err)
+						}
+					}
+				}()
+			}
+
+			if modalBtn.Clicked(gtx) {
+				modal = dialog.NewInputModal(dialog.InputDialogOptions{
+					Title:       "Modal Dialog",
+					Label:       "Enter something",
+					Description: "This dialog is drawn inside the demo window.",
+					DefaultText: "Default",
+				})
+				modal.OnResult = func(res dialog.Result) {
+					if res.Outcome == dialog.Confirmed {
+						resultText = fmt.Sprintf("Modal input: %q", res.Value)
+					} else {
+						resultText = "Modal " + res.Outcome.String()
+					}
+				}
+				modal.Invalidate = w.Invalidate
+			}
+
 			layout.Flex{Axis: layout.Vertical, Spacing: layout.SpaceAround}.Layout(gtx,
 				layout.Rigid(func(gtx layout.Context) layout.Dimensions {
 					return material.Button(th, &inputBtn, "Text Dialog").Layout(gtx)
 				}),
+				layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+					return material.Button(th, &passwordBtn, "Password Dialog").Layout(gtx)
+				}),
 				layout.Rigid(func(gtx layout.Context) layout.Dimensions {
 					return material.Button(th, &selectBtn, "Select Dialog").Layout(gtx)
 				}),
+				layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+					return material.Button(th, &multiSelectBtn, "Multi-Select Dialog").Layout(gtx)
+				}),
+				layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+					return material.Button(th, &openFileBtn, "Open File Dialog").Layout(gtx)
+				}),
+				layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+					return material.Button(th, &saveFileBtn, "Save File Dialog").Layout(gtx)
+				}),
+				layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+					return material.Button(th, &directoryBtn, "Directory Dialog").Layout(gtx)
+				}),
+				layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+					return material.Button(th, &progressBtn, "Progress Dialog").Layout(gtx)
+				}),
+				layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+					return material.Button(th, &formBtn, "Form Dialog").Layout(gtx)
+				}),
 				layout.Rigid(func(gtx layout.Context) layout.Dimensions {
 					return material.Button(th, &baseBtn, "Base Dialog").Layout(gtx)
 				}),
+				layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+					return material.Button(th, &questionBtn, "Question Dialog").Layout(gtx)
+				}),
+				layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+					return material.Button(th, &modalBtn, "Modal Dialog").Layout(gtx)
+				}),
 				layout.Rigid(func(gtx layout.Context) layout.Dimensions {
 					return material.Body1(th, resultText).Layout(gtx)
 				}),
 			)
 
+			// The modal dialog is drawn last, on top of the demo buttons.
+			if modal != nil {
+				modal.Layout(gtx, th)
+			}
+
 			e.Frame(gtx.Ops)
 		}
 	}
-	return nil
 }
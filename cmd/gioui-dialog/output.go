@@ -0,0 +1,87 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"time"
+
+	"github.com/gesellix/gioui-dialog/pkg/dialog"
+)
+
+// jsonResult is the result written with --output=json. Empty fields are
+// omitted, except for the outcome and the elapsed time.
+type jsonResult struct {
+	// Outcome is confirmed, canceled, dismissed, timeout or error.
+	Outcome string `json:"outcome"`
+	// Value is the entered text or password, or the selected row of a
+	// list.
+	Value string `json:"value,omitempty"`
+	// Values are the selected rows of a list with --multiple or
+	// --checklist.
+	Values []string `json:"values,omitempty"`
+	// Fields are the values of the fields of a form, by name. Passwords
+	// are strings.
+	Fields map[string]any `json:"fields,omitempty"`
+	// Button is the label of the button that closed the dialog.
+	Button string `json:"button,omitempty"`
+	// ElapsedMS is the time the dialog was shown, in milliseconds.
+	ElapsedMS int64 `json:"elapsed_ms"`
+	// Error is the cause of the error outcome.
+	Error string `json:"error,omitempty"`
+}
+
+// writeJSON writes the result of a dialog to w as a single line of JSON.
+// Unlike writeResult, it also writes the results of dialogs that weren't
+// confirmed, and the errors of dialogs that failed. With multiple, the
+// values of a multi-select dialog are written instead of its value.
+func writeJSON(w io.Writer, res dialog.Result, err error, multiple bool, elapsed time.Duration) {
+	out := jsonResult{
+		Outcome:   outcomeName(err),
+		Button:    res.Button.Label,
+		ElapsedMS: elapsed.Milliseconds(),
+	}
+	switch {
+	case res.Secret != nil:
+		out.Value = string(res.Secret)
+		clear(res.Secret)
+	case multiple:
+		out.Values = res.Values
+	default:
+		out.Value = res.Value
+	}
+	if res.Fields != nil {
+		out.Fields = make(map[string]any, len(res.Fields))
+		for name, v := range res.Fields {
+			if b, ok := v.([]byte); ok {
+				v = string(b)
+				clear(b)
+			}
+			out.Fields[name] = v
+		}
+	}
+	if out.Outcome == "error" {
+		out.Error = err.Error()
+	}
+	if err := json.NewEncoder(w).Encode(out); err != nil {
+		log.Println("Error writing the result:", err)
+	}
+}
+
+// outcomeName returns the outcome of --output=json for the error of a
+// dialog, matching its exit code.
+func outcomeName(err error) string {
+	switch {
+	case err == nil:
+		return "confirmed"
+	case errors.Is(err, dialog.ErrCanceled):
+		return "canceled"
+	case errors.Is(err, dialog.ErrDismissed):
+		return "dismissed"
+	case errors.Is(err, dialog.ErrTimeout):
+		return "timeout"
+	default:
+		return "error"
+	}
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gesellix/gioui-dialog/pkg/dialog"
+)
+
+func TestWriteJSON(t *testing.T) {
+	tests := []struct {
+		name     string
+		res      dialog.Result
+		err      error
+		multiple bool
+		want     string
+	}{
+		{
+			name: "confirmed",
+			res:  dialog.Result{Outcome: dialog.Confirmed, Value: "Alice", Button: dialog.Button{Label: "OK"}},
+			want: `{"outcome":"confirmed","value":"Alice","button":"OK","elapsed_ms":1500}`,
+		},
+		{
+			name: "password",
+			res:  dialog.Result{Outcome: dialog.Confirmed, Secret: []byte("s3cret"), Button: dialog.Button{Label: "OK"}},
+			want: `{"outcome":"confirmed","value":"s3cret","button":"OK","elapsed_ms":1500}`,
+		},
+		{
+			name:     "multiple",
+			res:      dialog.Result{Outcome: dialog.Confirmed, Values: []string{"a", "c"}},
+			multiple: true,
+			want:     `{"outcome":"confirmed","values":["a","c"],"elapsed_ms":1500}`,
+		},
+		{
+			name: "form",
+			res: dialog.Result{Outcome: dialog.Confirmed, Fields: map[string]any{
+				"user": "alice", "pin": []byte("1234"), "port": 22.0, "remember": true,
+			}},
+			want: `{"outcome":"confirmed","fields":{"pin":"1234","port":22,"remember":true,"user":"alice"},"elapsed_ms":1500}`,
+		},
+		{
+			name: "canceled",
+			res:  dialog.Result{Outcome: dialog.Canceled, Button: dialog.Button{Label: "No"}},
+			err:  dialog.ErrCanceled,
+			want: `{"outcome":"canceled","button":"No","elapsed_ms":1500}`,
+		},
+		{
+			name: "dismissed",
+			res:  dialog.Result{Outcome: dialog.Dismissed},
+			err:  dialog.ErrDismissed,
+			want: `{"outcome":"dismissed","elapsed_ms":1500}`,
+		},
+		{
+			name: "timeout",
+			res:  dialog.Result{Outcome: dialog.TimedOut, Value: "default"},
+			err:  dialog.ErrTimeout,
+			want: `{"outcome":"timeout","value":"default","elapsed_ms":1500}`,
+		},
+		{
+			name: "error",
+			res:  dialog.Result{Outcome: dialog.Error},
+			err:  errors.New("no display"),
+			want: `{"outcome":"error","elapsed_ms":1500,"error":"no display"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out strings.Builder
+			writeJSON(&out, tt.res, tt.err, tt.multiple, 1500*time.Millisecond)
+			if out.String() != tt.want+"\n" {
+				t.Errorf("got  %s\nwant %s", out.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteJSONClearsSecrets(t *testing.T) {
+	secret, pin := []byte("s3cret"), []byte("1234")
+	writeJSON(&strings.Builder{}, dialog.Result{Secret: secret, Fields: map[string]any{"pin": pin}}, nil, false, 0)
+	if string(secret) != "\x00\x00\x00\x00\x00\x00" || string(pin) != "\x00\x00\x00\x00" {
+		t.Errorf("secrets not cleared: %q, %q", secret, pin)
+	}
+}
+
+func TestOutcomeName(t *testing.T) {
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{nil, "confirmed"},
+		{dialog.ErrCanceled, "canceled"},
+		{dialog.ErrDismissed, "dismissed"},
+		{dialog.ErrTimeout, "timeout"},
+		{fmt.Errorf("list: %w", dialog.ErrTimeout), "timeout"},
+		{errors.New("no display"), "error"},
+	}
+	for _, tt := range tests {
+		if got := outcomeName(tt.err); got != tt.want {
+			t.Errorf("outcomeName(%v) = %q, want %q", tt.err, got, tt.want)
+		}
+		// The outcome matches the exit code.
+		if code := exitCode(tt.err); (code == exitOK) != (tt.want == "confirmed") || (code == exitError) != (tt.want == "error") {
+			t.Errorf("exitCode(%v) = %d for outcome %q", tt.err, code, tt.want)
+		}
+	}
+}
+
+func TestOutputFormat(t *testing.T) {
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{nil, "text"},
+		{[]string{"--entry"}, "text"},
+		{[]string{"--entry", "--output=json"}, "json"},
+		{[]string{"-output", "json", "--entry"}, "json"},
+		// Flags after an invalid one are still found
+		{[]string{"--calendar", "--output=json"}, "json"},
+		{[]string{"--list", "--title", "x", "--output", "json", "a", "b"}, "json"},
+		{[]string{"--output=json", "--output=text"}, "text"},
+		{[]string{"--list", "--", "--output=json"}, "text"},
+		{[]string{"--output"}, ""},
+	}
+	for _, tt := range tests {
+		if got := outputFormat(tt.args); got != tt.want {
+			t.Errorf("outputFormat(%q) = %q, want %q", tt.args, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/gesellix/gioui-dialog/pkg/dialog"
+)
+
+// runProgress shows a progress dialog that is updated with the lines read
+// from r, like zenity --progress, and returns its result.
+func runProgress(ctx context.Context, r io.Reader, opts dialog.ProgressDialogOptions) (dialog.Result, error) {
+	p, ctx := dialog.StartProgress(ctx, opts)
+	go readProgress(ctx, r, p)
+	return p.Wait()
+}
+
+// readProgress applies the lines read from r to p until the end of the
+// input, which completes the work, or until ctx is done:
+//
+//   - a number, e.g. "42", sets the percentage
+//   - "# text" sets the status text
+//   - "pulsate:true" and "pulsate:false" switch the pulsating bar on and off
+//
+// Other lines are ignored.
+func readProgress(ctx context.Context, r io.Reader, p *dialog.Progress) {
+	s := bufio.NewScanner(r)
+	for s.Scan() && ctx.Err() == nil {
+		line := strings.TrimSpace(s.Text())
+		if text, ok := strings.CutPrefix(line, "#"); ok {
+			p.SetText(strings.TrimSpace(text))
+		} else if pulsate, ok := strings.CutPrefix(line, "pulsate:"); ok {
+			if on, err := strconv.ParseBool(pulsate); err == nil {
+				p.SetPulsating(on)
+			}
+		} else if percent, err := strconv.ParseFloat(line, 64); err == nil {
+			p.SetPercent(percent)
+		}
+	}
+	if err := s.Err(); err != nil {
+		log.Println("Error reading the progress:", err)
+	} else if ctx.Err() == nil {
+		p.Complete()
+	}
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/gesellix/gioui-dialog/pkg/dialogrpc"
+)
+
+// runServe runs the serve command, which serves dialogs over JSON-RPC 2.0
+// on stdin and stdout, or on the Unix socket given by --socket, until the
+// end of stdin or until it is interrupted. It returns the exit code.
+func runServe(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("gioui-dialog serve", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	socket := fs.String("socket", "", "path of a Unix socket to listen on instead of stdin and stdout")
+	if err := fs.Parse(args); err != nil {
+		return exitError
+	}
+	if fs.NArg() > 0 {
+		fmt.Fprintln(stderr, "usage: gioui-dialog serve [--socket PATH]")
+		return exitError
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	server := dialogrpc.NewServer(dialogrpc.DialogBackend{})
+	var err error
+	if *socket == "" {
+		err = server.ServeConn(ctx, stdin, stdout)
+	} else {
+		var l net.Listener
+		if l, err = net.Listen("unix", *socket); err == nil {
+			err = server.Serve(ctx, l)
+		}
+	}
+	if err != nil && !errors.Is(err, context.Canceled) {
+		fmt.Fprintln(stderr, "Error serving dialogs:", err)
+		return exitError
+	}
+	return exitOK
+}
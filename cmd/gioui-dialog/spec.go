@@ -0,0 +1,117 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/gesellix/gioui-dialog/pkg/dialog"
+)
+
+// specCommands are the subcommands working with dialog specs, see
+// runSpecCommand.
+var specCommands = map[string]bool{"run": true, "validate": true, "schema": true}
+
+// runSpecCommand runs a subcommand working with dialog specs and returns
+// the exit code:
+//
+//   - run [--output=json] FILE shows the dialog described by the spec in
+//     FILE, or in stdin if FILE is "-", and writes its result like the
+//     zenity-compatible options
+//   - validate FILE... reports the problems of the specs
+//   - schema writes the JSON Schema of the specs
+func runSpecCommand(name string, args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("gioui-dialog "+name, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	output := "text"
+	if name == "run" {
+		fs.StringVar(&output, "output", "text", "format of the result: text, or json for an object with the outcome")
+	}
+	if err := fs.Parse(args); err != nil {
+		return exitError
+	}
+	if output != "text" && output != "json" {
+		fmt.Fprintf(stderr, "invalid value %q for --output: use text or json\n", output)
+		return exitError
+	}
+
+	switch {
+	case name == "schema":
+		io.WriteString(stdout, dialog.SpecSchema)
+		return exitOK
+	case name == "validate" && fs.NArg() > 0:
+		code := exitOK
+		for _, path := range fs.Args() {
+			if _, err := loadSpec(path, stdin); err != nil {
+				fmt.Fprintf(stderr, "%s: %v\n", path, err)
+				code = exitError
+			}
+		}
+		return code
+	case name == "run" && fs.NArg() == 1:
+		start := time.Now()
+		spec, err := loadSpec(fs.Arg(0), stdin)
+		res := dialog.Result{}
+		if err == nil {
+			res, err = spec.Run(context.Background())
+		}
+		if output == "json" {
+			writeJSON(stdout, res, err, spec != nil && spec.Type == "multiselect", time.Since(start))
+		} else if err == nil {
+			writeSpecResult(stdout, spec, res)
+		}
+		return exitCode(err)
+	default:
+		fmt.Fprintf(stderr, "usage: gioui-dialog run [--output=json] FILE, gioui-dialog validate FILE..., or gioui-dialog schema\n")
+		return exitError
+	}
+}
+
+// loadSpec loads the dialog spec in the file at path, or in stdin if path
+// is "-".
+func loadSpec(path string, stdin io.Reader) (*dialog.Spec, error) {
+	if path == "-" {
+		return dialog.LoadSpec(stdin)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return dialog.LoadSpec(f)
+}
+
+// writeSpecResult writes the result of a confirmed spec dialog to w: the
+// entered text or password, the selected choice, the checked choices one
+// per line, or a name=value line per field of a form.
+func writeSpecResult(w io.Writer, spec *dialog.Spec, res dialog.Result) {
+	switch spec.Type {
+	case "password":
+		w.Write(res.Secret)
+		io.WriteString(w, "\n")
+		clear(res.Secret)
+	case "input", "select":
+		fmt.Fprintln(w, res.Value)
+	case "multiselect":
+		for _, v := range res.Values {
+			fmt.Fprintln(w, v)
+		}
+	case "form":
+		for _, f := range spec.Fields {
+			switch v := res.Fields[f.Name].(type) {
+			case nil:
+				fmt.Fprintf(w, "%s=\n", f.Name)
+			case []byte:
+				fmt.Fprintf(w, "%s=%s\n", f.Name, v)
+				clear(v)
+			case time.Time:
+				fmt.Fprintf(w, "%s=%s\n", f.Name, v.Format(time.DateOnly))
+			default:
+				fmt.Fprintf(w, "%s=%v\n", f.Name, v)
+			}
+		}
+	}
+}
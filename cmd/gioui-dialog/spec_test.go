@@ -0,0 +1,95 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gesellix/gioui-dialog/pkg/dialog"
+)
+
+func TestSpecCommands(t *testing.T) {
+	dir := t.TempDir()
+	valid := filepath.Join(dir, "valid.yaml")
+	invalid := filepath.Join(dir, "invalid.yaml")
+	os.WriteFile(valid, []byte("type: question\nmessage: Continue?\n"), 0o600)
+	os.WriteFile(invalid, []byte("type: select\n"), 0o600)
+
+	tests := []struct {
+		name     string
+		args     []string
+		stdin    string
+		code     int
+		stdout   string // prefix of stdout
+		stderrOK string // substring of stderr
+	}{
+		{"validate", []string{"validate", valid}, "", exitOK, "", ""},
+		{"validate stdin", []string{"validate", "-"}, `{"type": "info"}`, exitOK, "", ""},
+		{"validate invalid", []string{"validate", valid, invalid}, "", exitError, "", "invalid.yaml: choices: missing"},
+		{"validate missing", []string{"validate", filepath.Join(dir, "nope.yaml")}, "", exitError, "", "nope.yaml"},
+		{"validate without files", []string{"validate"}, "", exitError, "", "usage:"},
+		{"schema", []string{"schema"}, "", exitOK, "{", ""},
+		{"run invalid", []string{"run", invalid}, "", exitError, "", ""},
+		{"run invalid json", []string{"run", "--output=json", invalid}, "", exitError, `{"outcome":"error",`, ""},
+		{"run invalid output", []string{"run", "--output=xml", valid}, "", exitError, "", "invalid value"},
+		{"run two files", []string{"run", valid, valid}, "", exitError, "", "usage:"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr strings.Builder
+			code := runSpecCommand(tt.args[0], tt.args[1:], strings.NewReader(tt.stdin), &stdout, &stderr)
+			if code != tt.code {
+				t.Errorf("exit code %d, want %d", code, tt.code)
+			}
+			if !strings.HasPrefix(stdout.String(), tt.stdout) || tt.stdout == "" && stdout.Len() > 0 {
+				t.Errorf("stdout %q, want %q", stdout.String(), tt.stdout)
+			}
+			if !strings.Contains(stderr.String(), tt.stderrOK) {
+				t.Errorf("stderr %q doesn't contain %q", stderr.String(), tt.stderrOK)
+			}
+		})
+	}
+}
+
+func TestSchemaCommand(t *testing.T) {
+	var stdout strings.Builder
+	runSpecCommand("schema", nil, strings.NewReader(""), &stdout, &strings.Builder{})
+	if !json.Valid([]byte(stdout.String())) {
+		t.Error("the schema isn't valid JSON")
+	}
+}
+
+func TestWriteSpecResult(t *testing.T) {
+	tests := []struct {
+		spec dialog.Spec
+		res  dialog.Result
+		want string
+	}{
+		{dialog.Spec{Type: "input"}, dialog.Result{Value: "Alice"}, "Alice\n"},
+		{dialog.Spec{Type: "password"}, dialog.Result{Secret: []byte("s3cret")}, "s3cret\n"},
+		{dialog.Spec{Type: "select"}, dialog.Result{Value: "b"}, "b\n"},
+		{dialog.Spec{Type: "multiselect"}, dialog.Result{Values: []string{"a", "c"}}, "a\nc\n"},
+		{dialog.Spec{Type: "info"}, dialog.Result{}, ""},
+		{
+			dialog.Spec{Type: "form", Fields: []dialog.FieldSpec{{Name: "user"}, {Name: "pin"}, {Name: "due"}, {Name: "port"}, {Name: "age"}}},
+			dialog.Result{Fields: map[string]any{
+				"user": "alice",
+				"pin":  []byte("1234"),
+				"due":  time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC),
+				"port": 22.0,
+				"age":  nil,
+			}},
+			"user=alice\npin=1234\ndue=2026-10-16\nport=22\nage=\n",
+		},
+	}
+	for _, tt := range tests {
+		var out strings.Builder
+		writeSpecResult(&out, &tt.spec, tt.res)
+		if out.String() != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.spec.Type, out.String(), tt.want)
+		}
+	}
+}
@@ -2,7 +2,10 @@ module github.com/gesellix/gioui-dialog
 
 go 1.25.0
 
-require gioui.org v0.10.2
+require (
+	gioui.org v0.10.2
+	gopkg.in/yaml.v3 v3.0.1
+)
 
 require (
 	gioui.org/shader v1.0.9 // indirect
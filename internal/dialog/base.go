@@ -1,37 +1,57 @@
 package dialog
 
 import (
-	"sync"
 	"time"
 
-	"gioui.org/app"
-	"gioui.org/io/key"
-	"gioui.org/io/system"
+	"gioui.org/io/event"
 	"gioui.org/layout"
-	"gioui.org/op"
-	"gioui.org/unit"
 	"gioui.org/widget"
 	"gioui.org/widget/material"
 )
 
-// BaseDialog contains common properties and (later) shared behavior
-// used by concrete dialogs (InputDialog, SelectDialog).
+// BaseDialog contains common properties and shared behavior used by
+// concrete dialogs (InputDialog, SelectDialog), which embed it.
 // According to SPEC.md it provides Title, Label, Description and
 // standard OK/Cancel handling.
+//
+// On its own, BaseDialog is a Dialog without a body, i.e. a simple
+// confirmation dialog.
 type BaseDialog struct {
 	Width, Height float32
 	Title         string
 	Label         string
 	Description   string
 
+	// Buttons replace the default Cancel and OK buttons. Without a
+	// button for Escape, Escape cancels the dialog anyway.
+	Buttons []Button
+
+	// Timeout closes the dialog automatically unless the user interacts
+	// with it before; TimeoutAction defines how it is closed.
+	Timeout       time.Duration
+	TimeoutAction TimeoutAction
+
 	// internal result state
-	confirmed bool
-	canceled  bool
+	outcome Outcome
+	pressed Button
+	// finished closes the dialog with outcome on the next frame, without
+	// a button press, e.g. once the work of a progress dialog is done.
+	finished bool
 
 	// UI state
-	okButton     widget.Clickable
-	cancelButton widget.Clickable
-	done         bool
+	focused      bool
+	countdown    countdown
+	buttons      []Button
+	buttonClicks []widget.Clickable
+
+	// invalidate requests a new frame, see Dialog.open.
+	invalidate func()
+
+	// nested is a dialog shown on top of this one, see ask.
+	nested     *Modal
+	nestedDone func(Result) bool
+	// askedBy is the index of the button whose press opened nested, or -1.
+	askedBy int
 }
 
 // NewBaseDialog creates a new BaseDialog with the standard fields.
@@ -51,104 +71,41 @@ func NewBaseDialog(width, height float32, title, label, description string) *Bas
 	}
 }
 
-// Show runs the base dialog event loop and returns whether the dialog was
-// confirmed, canceled, and any error that occurred.
-func (b *BaseDialog) Show() (confirmed bool, canceled bool, err error) {
-	w := app.Window{}
-	w.Option(
-		app.Title(b.Title),
-		app.Size(unit.Dp(b.Width), unit.Dp(b.Height)),
-	)
-	// TODO work around https://todo.sr.ht/~eliasnaur/gio/602 (still an issue in gio v0.8.0?)
-	// this should only be required shortly after creating the window w.
-	// It doesn't work with the current gio version (0.8.1-dev), which only includes a fix for os_windows.
-	applyWindowOptions := sync.OnceFunc(func() {
-		time.Sleep(50 * time.Millisecond)
-		w.Perform(system.ActionCenter | system.ActionRaise)
-	})
-	w.Perform(system.ActionCenter | system.ActionRaise)
-
-	th := material.NewTheme()
-	var ops op.Ops
-
-	for !b.done {
-		switch e := w.Event().(type) {
-		case app.FrameEvent:
-			applyWindowOptions()
-			gtx := app.NewContext(&ops, e)
-			if b.cancelButton.Clicked(gtx) {
-				b.handleCancel()
-				w.Perform(system.ActionClose)
-			}
-			if b.okButton.Clicked(gtx) {
-				b.handleOK()
-				w.Perform(system.ActionClose)
-			}
-			b.layout(gtx, th)
-			e.Frame(gtx.Ops)
-		case key.Event:
-			// Handle Escape key for cancel
-			if e.Name == key.NameEscape && e.State == key.Press {
-				b.handleCancel()
-				w.Perform(system.ActionClose)
-			}
-			// Handle Enter key for OK
-			if e.Name == key.NameReturn && e.State == key.Press {
-				b.handleOK()
-				w.Perform(system.ActionClose)
-			}
-		case app.DestroyEvent:
-			b.done = true
-			return b.confirmed, b.canceled, e.Err
-		}
+func (b *BaseDialog) base() *BaseDialog { return b }
+
+// open prepares the buttons and the timeout before the dialog is shown.
+// Dialogs that override open must call it.
+func (b *BaseDialog) open(invalidate func()) {
+	b.buttons = b.Buttons
+	if len(b.buttons) == 0 {
+		b.buttons = defaultButtons
 	}
-	//app.Main()
-	return b.confirmed, b.canceled, nil
+	b.buttonClicks = make([]widget.Clickable, len(b.buttons))
+	b.countdown.timeout = b.Timeout
+	b.invalidate = invalidate
 }
 
-func (b *BaseDialog) layout(gtx layout.Context, th *material.Theme) layout.Dimensions {
-	return layout.UniformInset(unit.Dp(20)).Layout(gtx, func(gtx layout.Context) layout.Dimensions {
-		return layout.Flex{Axis: layout.Vertical, Spacing: layout.SpaceAround}.Layout(gtx,
-			// Label
-			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
-				label := material.H6(th, b.Label)
-				return label.Layout(gtx)
-			}),
-			// Description
-			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
-				if b.Description == "" {
-					return layout.Dimensions{}
-				}
-				desc := material.Body1(th, b.Description)
-				desc.Color = th.Fg
-				return desc.Layout(gtx)
-			}),
-			// Buttons
-			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
-				return layout.Flex{Axis: layout.Horizontal, Spacing: layout.SpaceEnd}.Layout(gtx,
-					layout.Rigid(func(gtx layout.Context) layout.Dimensions {
-						btn := material.Button(th, &b.cancelButton, "Cancel")
-						return btn.Layout(gtx)
-					}),
-					layout.Rigid(func(gtx layout.Context) layout.Dimensions {
-						return layout.Spacer{Width: unit.Dp(10)}.Layout(gtx)
-					}),
-					layout.Rigid(func(gtx layout.Context) layout.Dimensions {
-						btn := material.Button(th, &b.okButton, "OK")
-						return btn.Layout(gtx)
-					}),
-				)
-			}),
-		)
-	})
-}
+func (b *BaseDialog) close() {}
 
-func (b *BaseDialog) handleOK() {
-	b.confirmed = true
-	b.canceled = false
+// ask shows nested on top of the dialog, which ignores input until the
+// user closed nested. Then done is called with its result. If ask was
+// called by confirm, and done returns true, the button press is repeated,
+// e.g. once the user agreed to overwrite a file.
+func (b *BaseDialog) ask(nested Dialog, done func(Result) bool) {
+	b.countdown.pause()
+	b.nested, b.nestedDone, b.askedBy = NewModal(nested, b.invalidate), done, -1
 }
 
-func (b *BaseDialog) handleCancel() {
-	b.confirmed = false
-	b.canceled = true
+func (b *BaseDialog) initialFocus() event.Tag { return nil }
+
+func (b *BaseDialog) update(gtx layout.Context) bool { return false }
+
+func (b *BaseDialog) layoutBody(gtx layout.Context, th *material.Theme) layout.Dimensions {
+	return layout.Dimensions{}
 }
+
+func (b *BaseDialog) canConfirm() bool { return true }
+
+func (b *BaseDialog) confirm() bool { return true }
+
+func (b *BaseDialog) result() Result { return Result{Outcome: b.outcome} }
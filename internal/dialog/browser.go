@@ -0,0 +1,420 @@
+package dialog
+
+import (
+	"cmp"
+	"fmt"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"gioui.org/io/key"
+	"gioui.org/layout"
+	"gioui.org/text"
+	"gioui.org/unit"
+	"gioui.org/widget"
+	"gioui.org/widget/material"
+)
+
+// FileFilter limits the files a file dialog lists to those whose name
+// matches one of Patterns, e.g. "*.go". Patterns use the syntax of
+// path.Match and ignore case. Directories are always listed.
+type FileFilter struct {
+	Name     string
+	Patterns []string
+}
+
+// label returns the name of the filter, or its patterns if it has none.
+func (f FileFilter) label() string {
+	return cmp.Or(f.Name, strings.Join(f.Patterns, ", "))
+}
+
+// matches reports whether the file name matches one of the patterns.
+func (f FileFilter) matches(name string) bool {
+	name = strings.ToLower(name)
+	for _, p := range f.Patterns {
+		if ok, _ := path.Match(strings.ToLower(p), name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// sortKey is the column the entries of a fileBrowser are sorted by.
+type sortKey int
+
+const (
+	sortByName sortKey = iota
+	sortBySize
+	sortByDate
+)
+
+// fileEntry is a directory entry listed by a fileBrowser.
+type fileEntry struct {
+	name    string
+	dir     bool
+	size    int64
+	modTime time.Time
+}
+
+// fileBrowser lists the entries of a directory of an fs.FS, with a
+// breadcrumb path bar, sortable columns, a hidden-file toggle and file
+// filters. It is shared by the file dialogs, which decide what a click
+// on an entry does.
+type fileBrowser struct {
+	FS fs.FS
+	// Dir is the directory shown, as a path of FS ("." is the root).
+	Dir string
+	// Root is the directory of the operating system FS is rooted at, if
+	// any. Selected paths are then returned as paths of the operating
+	// system, and Root labels the root in the path bar.
+	Root       string
+	ShowHidden bool
+	Filters    []FileFilter
+	// DirsOnly hides files, e.g. for directory pickers.
+	DirsOnly bool
+
+	// all holds the entries of Dir, and entries the visible ones in order.
+	all     []fileEntry
+	entries []fileEntry
+	err     error
+
+	sortKey    sortKey
+	descending bool
+	filter     int
+
+	// UI state
+	crumbs     []widget.Clickable
+	headers    [3]widget.Clickable
+	rows       []widget.Clickable
+	hidden     widget.Bool
+	filterEnum widget.Enum
+	list       widget.List
+}
+
+// load reads the directory dir and shows it. If it can't be read, the
+// browser stays in the previous directory and shows the error.
+func (b *fileBrowser) load(dir string) {
+	dir = path.Clean(dir)
+	des, err := fs.ReadDir(b.FS, dir)
+	if err != nil {
+		b.err = err
+		return
+	}
+	b.Dir, b.err = dir, nil
+	b.all = b.all[:0]
+	for _, de := range des {
+		e := fileEntry{name: de.Name(), dir: de.IsDir()}
+		if info, err := de.Info(); err == nil {
+			e.size, e.modTime = info.Size(), info.ModTime()
+		}
+		// List symbolic links to directories as directories
+		if de.Type()&fs.ModeSymlink != 0 {
+			if info, err := fs.Stat(b.FS, path.Join(dir, e.name)); err == nil {
+				e.dir = info.IsDir()
+			}
+		}
+		b.all = append(b.all, e)
+	}
+	b.list.Position = layout.Position{}
+	b.refresh()
+}
+
+// refresh applies the hidden-file toggle, the filter and the sort order
+// to the entries of the directory.
+func (b *fileBrowser) refresh() {
+	b.entries = b.entries[:0]
+	for _, e := range b.all {
+		if b.visible(e) {
+			b.entries = append(b.entries, e)
+		}
+	}
+	slices.SortStableFunc(b.entries, func(x, y fileEntry) int {
+		// Directories come first in either direction
+		if x.dir != y.dir {
+			if x.dir {
+				return -1
+			}
+			return 1
+		}
+		var c int
+		switch b.sortKey {
+		case sortBySize:
+			c = cmp.Compare(x.size, y.size)
+		case sortByDate:
+			c = x.modTime.Compare(y.modTime)
+		}
+		c = cmp.Or(c, cmp.Compare(strings.ToLower(x.name), strings.ToLower(y.name)), cmp.Compare(x.name, y.name))
+		if b.descending {
+			return -c
+		}
+		return c
+	})
+	if len(b.rows) < len(b.entries) {
+		b.rows = make([]widget.Clickable, len(b.entries))
+	}
+}
+
+// visible reports whether e is listed.
+func (b *fileBrowser) visible(e fileEntry) bool {
+	if !b.ShowHidden && strings.HasPrefix(e.name, ".") {
+		return false
+	}
+	if e.dir {
+		return true
+	}
+	if b.DirsOnly {
+		return false
+	}
+	return len(b.Filters) == 0 || b.Filters[b.filter].matches(e.name)
+}
+
+// setSort sorts the entries by key, or reverses the order if they are
+// already sorted by key.
+func (b *fileBrowser) setSort(key sortKey) {
+	if b.sortKey == key {
+		b.descending = !b.descending
+	} else {
+		b.sortKey, b.descending = key, false
+	}
+	b.refresh()
+}
+
+// path returns the path of the entry named name in the current directory.
+func (b *fileBrowser) path(name string) string {
+	return path.Join(b.Dir, name)
+}
+
+// osPath converts p, a path of FS, into a path of the operating system if
+// Root is set.
+func (b *fileBrowser) osPath(p string) string {
+	if b.Root == "" {
+		return p
+	}
+	return filepath.Join(b.Root, filepath.FromSlash(p))
+}
+
+// segments returns the directory names leading from the root to Dir.
+func (b *fileBrowser) segments() []string {
+	if b.Dir == "." {
+		return nil
+	}
+	return strings.Split(b.Dir, "/")
+}
+
+// update processes the controls of the browser. It returns the index of
+// the entry that was clicked, if any, and whether it was activated by a
+// double click or by Enter. Navigating to another directory reports
+// changed.
+func (b *fileBrowser) update(gtx layout.Context) (clicked int, activate, changed bool) {
+	dir := b.Dir
+	segments := b.segments()
+	for i := range b.crumbs {
+		if b.crumbs[i].Clicked(gtx) && i <= len(segments) {
+			b.load(cmp.Or(path.Join(segments[:i]...), "."))
+		}
+	}
+	for key := range b.headers {
+		if b.headers[key].Clicked(gtx) {
+			b.setSort(sortKey(key))
+		}
+	}
+	if b.hidden.Update(gtx) {
+		b.ShowHidden = b.hidden.Value
+		b.refresh()
+	}
+	if b.filterEnum.Update(gtx) {
+		b.filter, _ = strconv.Atoi(b.filterEnum.Value)
+		b.refresh()
+	}
+
+	clicked = -1
+	for i := range b.entries {
+		// Enter activates the focused entry, instead of clicking it
+		for {
+			ev, ok := gtx.Event(
+				key.Filter{Focus: &b.rows[i], Name: key.NameReturn},
+				key.Filter{Focus: &b.rows[i], Name: key.NameEnter},
+			)
+			if !ok {
+				break
+			}
+			if e, ok := ev.(key.Event); ok && e.State == key.Press {
+				clicked, activate = i, true
+			}
+		}
+		for {
+			click, ok := b.rows[i].Update(gtx)
+			if !ok {
+				break
+			}
+			clicked, activate = i, click.NumClicks > 1
+		}
+	}
+	return clicked, activate, b.Dir != dir
+}
+
+// layout lays out the path bar, the toolbar and the list of entries.
+// marked reports which entries are highlighted.
+func (b *fileBrowser) layout(gtx layout.Context, th *material.Theme, marked func(fileEntry) bool) layout.Dimensions {
+	b.hidden.Value = b.ShowHidden
+	b.filterEnum.Value = strconv.Itoa(b.filter)
+	return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+		layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+			return b.layoutPathBar(gtx, th)
+		}),
+		layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+			return b.layoutToolbar(gtx, th)
+		}),
+		layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+			return b.layoutHeaders(gtx, th)
+		}),
+		layout.Flexed(1, func(gtx layout.Context) layout.Dimensions {
+			border := widget.Border{Color: th.Fg, Width: unit.Dp(1), CornerRadius: unit.Dp(4)}
+			border.Color.A = 0x40
+			return border.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+				if len(b.entries) == 0 {
+					gtx.Constraints.Min = gtx.Constraints.Max
+					return layout.UniformInset(unit.Dp(8)).Layout(gtx, material.Caption(th, "Empty folder").Layout)
+				}
+				materialList := material.List(th, &b.list)
+				b.list.Axis = layout.Vertical
+				return materialList.Layout(gtx, len(b.entries), func(gtx layout.Context, i int) layout.Dimensions {
+					return b.layoutEntry(gtx, th, i, marked(b.entries[i]))
+				})
+			})
+		}),
+		// Error reading a directory
+		layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+			if b.err == nil {
+				return layout.Dimensions{}
+			}
+			msg := material.Caption(th, b.err.Error())
+			msg.Color = errorColor
+			return msg.Layout(gtx)
+		}),
+	)
+}
+
+// layoutPathBar lays out a button for the root and for every directory
+// leading to the current one.
+func (b *fileBrowser) layoutPathBar(gtx layout.Context, th *material.Theme) layout.Dimensions {
+	names := append([]string{cmp.Or(b.Root, "/")}, b.segments()...)
+	if len(b.crumbs) < len(names) {
+		b.crumbs = make([]widget.Clickable, len(names))
+	}
+	children := make([]layout.FlexChild, 0, 2*len(names))
+	for i, name := range names {
+		if i > 0 {
+			children = append(children, layout.Rigid(material.Body2(th, "›").Layout))
+		}
+		children = append(children, layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+			return flatButton(gtx, th, &b.crumbs[i], name)
+		}))
+	}
+	return layout.Flex{Axis: layout.Horizontal, Alignment: layout.Middle}.Layout(gtx, children...)
+}
+
+// layoutToolbar lays out the hidden-file toggle and the filters.
+func (b *fileBrowser) layoutToolbar(gtx layout.Context, th *material.Theme) layout.Dimensions {
+	children := []layout.FlexChild{
+		layout.Rigid(material.CheckBox(th, &b.hidden, "Show hidden files").Layout),
+		layout.Flexed(1, func(gtx layout.Context) layout.Dimensions {
+			return layout.Dimensions{Size: gtx.Constraints.Min}
+		}),
+	}
+	if !b.DirsOnly {
+		for i, f := range b.Filters {
+			children = append(children, layout.Rigid(material.RadioButton(th, &b.filterEnum, strconv.Itoa(i), f.label()).Layout))
+		}
+	}
+	return layout.Flex{Axis: layout.Horizontal, Alignment: layout.Middle}.Layout(gtx, children...)
+}
+
+// Widths of the size and date columns.
+const (
+	sizeColumn = unit.Dp(72)
+	dateColumn = unit.Dp(120)
+)
+
+func (b *fileBrowser) layoutHeaders(gtx layout.Context, th *material.Theme) layout.Dimensions {
+	header := func(key sortKey, title string) layout.Widget {
+		return func(gtx layout.Context) layout.Dimensions {
+			switch {
+			case b.sortKey == key && b.descending:
+				title += " ▼"
+			case b.sortKey == key:
+				title += " ▲"
+			}
+			return flatButton(gtx, th, &b.headers[key], title)
+		}
+	}
+	return layout.Flex{Axis: layout.Horizontal}.Layout(gtx,
+		layout.Flexed(1, header(sortByName, "Name")),
+		layout.Rigid(column(sizeColumn, header(sortBySize, "Size"))),
+		layout.Rigid(column(dateColumn, header(sortByDate, "Modified"))),
+	)
+}
+
+// layoutEntry lays out the entry at index i, highlighted if marked.
+func (b *fileBrowser) layoutEntry(gtx layout.Context, th *material.Theme, i int, marked bool) layout.Dimensions {
+	e := b.entries[i]
+	btn := material.ButtonLayout(th, &b.rows[i])
+	btn.CornerRadius = 0
+	fg := th.Fg
+	btn.Background = th.Bg
+	if marked {
+		btn.Background, fg = th.Palette.ContrastBg, th.Palette.ContrastFg
+	}
+	cell := func(s string, align text.Alignment) layout.Widget {
+		return func(gtx layout.Context) layout.Dimensions {
+			l := material.Body2(th, s)
+			l.MaxLines = 1
+			l.Alignment = align
+			l.Color = fg
+			gtx.Constraints.Min.X = gtx.Constraints.Max.X
+			return l.Layout(gtx)
+		}
+	}
+	name, size := e.name, formatSize(e.size)
+	if e.dir {
+		name, size = name+"/", ""
+	}
+	date := ""
+	if !e.modTime.IsZero() {
+		date = e.modTime.Format("2006-01-02 15:04")
+	}
+	return btn.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+		return layout.Inset{Top: 6, Bottom: 6, Left: 8, Right: 8}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+			return layout.Flex{Axis: layout.Horizontal}.Layout(gtx,
+				layout.Flexed(1, cell(name, text.Start)),
+				layout.Rigid(column(sizeColumn, cell(size, text.End))),
+				layout.Rigid(column(dateColumn, cell(date, text.End))),
+			)
+		})
+	})
+}
+
+// column lays out w with a fixed width.
+func column(width unit.Dp, w layout.Widget) layout.Widget {
+	return func(gtx layout.Context) layout.Dimensions {
+		gtx.Constraints.Min.X = gtx.Dp(width)
+		gtx.Constraints.Max.X = gtx.Constraints.Min.X
+		return w(gtx)
+	}
+}
+
+// formatSize formats a file size in bytes for humans.
+func formatSize(n int64) string {
+	if n < 1024 {
+		return fmt.Sprintf("%d B", n)
+	}
+	size, prefixes := float64(n)/1024, "KMGTPE"
+	for size >= 1024 && len(prefixes) > 1 {
+		size, prefixes = size/1024, prefixes[1:]
+	}
+	return fmt.Sprintf("%.1f %cB", size, prefixes[0])
+}
@@ -0,0 +1,103 @@
+package dialog
+
+import (
+	"gioui.org/layout"
+	"gioui.org/unit"
+	"gioui.org/widget/material"
+)
+
+// ButtonRole defines what a dialog button does, and how it looks.
+type ButtonRole int
+
+const (
+	// RoleAffirmative accepts the input and confirms the dialog, e.g. OK or
+	// Save. It is disabled as long as the input can't be confirmed.
+	RoleAffirmative ButtonRole = iota
+	// RoleNegative cancels the dialog, e.g. Cancel.
+	RoleNegative
+	// RoleNeutral closes the dialog without accepting the input, like
+	// Cancel, but with a different meaning, e.g. Later or Skip.
+	RoleNeutral
+	// RoleDestructive is an affirmative button for an action that can't be
+	// undone, e.g. Delete or Discard. It is drawn in red.
+	RoleDestructive
+)
+
+// Button describes a dialog button.
+type Button struct {
+	Label string
+	Role  ButtonRole
+	// Default makes Enter press the button.
+	Default bool
+	// Cancel makes Escape press the button.
+	Cancel bool
+}
+
+// accepts reports whether the button accepts the input.
+func (b Button) accepts() bool {
+	return b.Role == RoleAffirmative || b.Role == RoleDestructive
+}
+
+// defaultButtons are used by dialogs without custom buttons.
+var defaultButtons = []Button{
+	{Label: "Cancel", Role: RoleNegative, Cancel: true},
+	{Label: "OK", Role: RoleAffirmative, Default: true},
+}
+
+// buttonIndex returns the index of the first button for which match
+// returns true, or -1.
+func (b *BaseDialog) buttonIndex(match func(Button) bool) int {
+	for i, btn := range b.buttons {
+		if match(btn) {
+			return i
+		}
+	}
+	return -1
+}
+
+// timeoutButton returns the index of the button that shows the countdown:
+// the default button if the timeout confirms the dialog, or the cancel
+// button otherwise. Without such a button, the last one is used.
+func (b *BaseDialog) timeoutButton() int {
+	i := b.buttonIndex(func(btn Button) bool {
+		if b.TimeoutAction == TimeoutConfirm {
+			return btn.Default
+		}
+		return btn.Cancel
+	})
+	if i < 0 {
+		i = len(b.buttons) - 1
+	}
+	return i
+}
+
+// layoutButtons lays out the buttons of d in a row.
+func layoutButtons(gtx layout.Context, th *material.Theme, d Dialog) layout.Dimensions {
+	b := d.base()
+	countdown := b.timeoutButton()
+	children := make([]layout.FlexChild, 0, 2*len(b.buttons))
+	for i, btn := range b.buttons {
+		if i > 0 {
+			children = append(children, layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return layout.Spacer{Width: unit.Dp(10)}.Layout(gtx)
+			}))
+		}
+		children = append(children, layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+			text := btn.Label
+			if i == countdown {
+				text = b.countdown.label(gtx, text)
+			}
+			// Buttons accepting the input stay disabled as long as it
+			// can't be confirmed
+			if btn.accepts() && !d.canConfirm() {
+				gtx = gtx.Disabled()
+			}
+			style := material.Button(th, &b.buttonClicks[i], text)
+			if btn.Role == RoleDestructive {
+				style.Background = errorColor
+			}
+			return style.Layout(gtx)
+		}))
+	}
+	return layout.Flex{Axis: layout.Horizontal, Spacing: layout.SpaceEnd}.Layout(gtx, children...)
+}
@@ -0,0 +1,82 @@
+package dialog
+
+import (
+	"errors"
+	"testing"
+
+	"gioui.org/io/key"
+)
+
+var (
+	saveButton    = Button{Label: "Save", Role: RoleAffirmative, Default: true}
+	discardButton = Button{Label: "Discard", Role: RoleDestructive}
+	cancelButton  = Button{Label: "Cancel", Role: RoleNegative, Cancel: true}
+)
+
+func TestButtons(t *testing.T) {
+	tests := []struct {
+		name    string
+		buttons []Button
+		// focus is the index of the button focused before pressing key.
+		focus   int
+		key     key.Name
+		want    Outcome
+		pressed Button
+	}{
+		{"default/enter", nil, -1, key.NameReturn, Confirmed, defaultButtons[1]},
+		{"default/escape", nil, -1, key.NameEscape, Canceled, defaultButtons[0]},
+		{"custom/enter", []Button{cancelButton, discardButton, saveButton}, -1, key.NameReturn, Confirmed, saveButton},
+		{"custom/escape", []Button{cancelButton, discardButton, saveButton}, -1, key.NameEscape, Canceled, cancelButton},
+		{"custom/click", []Button{cancelButton, discardButton, saveButton}, 1, key.NameSpace, Confirmed, discardButton},
+		{"neutral/click", []Button{{Label: "Later", Role: RoleNeutral}, saveButton}, 0, key.NameSpace, Canceled, Button{Label: "Later", Role: RoleNeutral}},
+		{"no-cancel/escape", []Button{saveButton}, -1, key.NameEscape, Canceled, Button{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := NewBaseDialog(0, 0, "", "", "")
+			d.Buttons = tt.buttons
+			h := newHarness(t, d)
+			if tt.focus >= 0 {
+				h.router.Source().Execute(key.FocusCmd{Tag: &d.buttonClicks[tt.focus]})
+				h.frame()
+			}
+			if !h.press(tt.key) {
+				t.Fatalf("dialog still open after %s", tt.key)
+			}
+			got := result(d)
+			if got.Outcome != tt.want || got.Button != tt.pressed {
+				t.Errorf("got %s with %+v, want %s with %+v", got.Outcome, got.Button, tt.want, tt.pressed)
+			}
+		})
+	}
+}
+
+func TestButtonsWithoutDefault(t *testing.T) {
+	d := NewBaseDialog(0, 0, "", "", "")
+	d.Buttons = []Button{cancelButton, discardButton}
+	h := newHarness(t, d)
+	if h.press(key.NameReturn) {
+		t.Fatal("Enter closed a dialog without a default button")
+	}
+}
+
+func TestButtonsRespectValidation(t *testing.T) {
+	d := NewInputDialog(0, 0, "", "", "", "", func(s string) error {
+		if s == "" {
+			return errors.New("required")
+		}
+		return nil
+	})
+	d.Buttons = []Button{cancelButton, {Label: "Skip", Role: RoleNeutral}, saveButton}
+	h := newHarness(t, d)
+
+	// Skip closes the dialog without accepting the invalid input
+	h.router.Source().Execute(key.FocusCmd{Tag: &d.buttonClicks[1]})
+	h.frame()
+	if !h.press(key.NameSpace) {
+		t.Fatal("Skip didn't close the dialog")
+	}
+	if got := result(d); got.Outcome != Canceled || got.Value != "" || got.Button.Label != "Skip" {
+		t.Errorf("got %+v, want Canceled by Skip", got)
+	}
+}
@@ -0,0 +1,107 @@
+package dialog
+
+import (
+	"context"
+	"errors"
+	"image"
+	"testing"
+	"time"
+
+	"gioui.org/app"
+	"gioui.org/io/event"
+	"gioui.org/io/system"
+	"gioui.org/op"
+	"gioui.org/unit"
+)
+
+// fakeWindow delivers a frame whenever it is invalidated, and destroys
+// itself when it is asked to close.
+type fakeWindow struct {
+	events chan event.Event
+	frames chan struct{} // receives a value after every frame
+}
+
+func newFakeWindow() *fakeWindow {
+	w := &fakeWindow{events: make(chan event.Event, 8), frames: make(chan struct{}, 8)}
+	w.Invalidate()
+	return w
+}
+
+func (w *fakeWindow) Event() event.Event { return <-w.events }
+
+func (w *fakeWindow) Perform(actions system.Action) {
+	if actions&system.ActionClose != 0 {
+		w.events <- app.DestroyEvent{}
+	}
+}
+
+func (w *fakeWindow) Invalidate() {
+	select {
+	case w.events <- app.FrameEvent{
+		Now:    time.Now(),
+		Metric: unit.Metric{PxPerDp: 1, PxPerSp: 1},
+		Size:   image.Pt(400, 300),
+		Frame: func(*op.Ops) {
+			select {
+			case w.frames <- struct{}{}:
+			default:
+			}
+		},
+	}:
+	default:
+	}
+}
+
+func TestRunContextDone(t *testing.T) {
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	expired, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancel()
+
+	for _, ctx := range []context.Context{canceled, expired} {
+		// Run returns before it opens a window.
+		res := Run(ctx, NewInputDialog(400, 300, "Title", "Label", "", "", nil))
+		if res.Outcome != Error || res.Err != ctx.Err() {
+			t.Errorf("Run() = %v %v, want %v", res.Outcome, res.Err, ctx.Err())
+		}
+	}
+}
+
+func TestLoopContextDone(t *testing.T) {
+	tests := []struct {
+		name string
+		ctx  func() (context.Context, context.CancelFunc)
+		want error
+	}{
+		{"canceled", func() (context.Context, context.CancelFunc) {
+			return context.WithCancel(context.Background())
+		}, context.Canceled},
+		{"deadline", func() (context.Context, context.CancelFunc) {
+			return context.WithTimeout(context.Background(), 50*time.Millisecond)
+		}, context.DeadlineExceeded},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := tt.ctx()
+			defer cancel()
+			w := newFakeWindow()
+			done := make(chan Result)
+			go func() {
+				done <- loop(ctx, w, NewInputDialog(400, 300, "Title", "Label", "", "", nil), func() {})
+			}()
+			// The dialog is open.
+			<-w.frames
+			if tt.want == context.Canceled {
+				cancel()
+			}
+			select {
+			case res := <-done:
+				if res.Outcome != Error || !errors.Is(res.Err, tt.want) {
+					t.Errorf("loop() = %v %v, want %v", res.Outcome, res.Err, tt.want)
+				}
+			case <-time.After(5 * time.Second):
+				t.Fatal("the dialog wasn't closed")
+			}
+		})
+	}
+}
@@ -0,0 +1,394 @@
+package dialog
+
+import (
+	"cmp"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"gioui.org/io/event"
+	"gioui.org/io/key"
+	"gioui.org/layout"
+	"gioui.org/op/clip"
+	"gioui.org/unit"
+	"gioui.org/widget"
+	"gioui.org/widget/material"
+)
+
+// dirNode is a directory in the tree of a directoryDialog. Its children
+// are read when it is expanded for the first time.
+type dirNode struct {
+	path     string
+	name     string
+	depth    int
+	parent   *dirNode
+	children []*dirNode
+	loaded   bool
+	expanded bool
+	err      error
+
+	// UI state
+	row    widget.Clickable
+	toggle widget.Clickable
+}
+
+// directoryDialog is the internal implementation of a directory picker: a
+// tree of the directories of an fs.FS, whose children are read lazily.
+type directoryDialog struct {
+	BaseDialog
+	FS fs.FS
+	// Dir is the directory selected first, as a path of FS.
+	Dir string
+	// Root is the directory of the operating system FS is rooted at, if
+	// any. The selected path is then returned as a path of the operating
+	// system.
+	Root       string
+	ShowHidden bool
+	// Validate checks the selected directory, given as a path of FS. The
+	// dialog can't be confirmed while it returns an error, which is shown
+	// below the tree.
+	Validate func(dir string) error
+	// ValidateConfirm checks the selected directory like Validate, but
+	// only when the user confirms it, e.g. because the check creates
+	// files.
+	ValidateConfirm func(dir string) error
+
+	// internal result state
+	path          string
+	validationErr error
+
+	tree     *dirNode
+	selected *dirNode
+	// visible holds the nodes of the expanded part of the tree, in order.
+	visible []*dirNode
+
+	// UI state
+	list            widget.List
+	newFolderButton widget.Clickable
+}
+
+// NewDirectoryDialog initializes a directoryDialog from provided parameters.
+func NewDirectoryDialog(width, height float32, title, label, description string, fsys fs.FS, dir string) *directoryDialog {
+	if width <= 0 {
+		width = 480
+	}
+	if height <= 0 {
+		height = 480
+	}
+	d := &directoryDialog{
+		BaseDialog: *NewBaseDialog(width, height, title, label, description),
+		FS:         fsys,
+		Dir:        dir,
+	}
+	d.Buttons = []Button{
+		{Label: "Cancel", Role: RoleNegative, Cancel: true},
+		{Label: "Select", Role: RoleAffirmative, Default: true},
+	}
+	d.list.Axis = layout.Vertical
+	return d
+}
+
+// open builds the tree, expanded down to Dir, which is selected.
+func (d *directoryDialog) open(invalidate func()) {
+	d.BaseDialog.open(invalidate)
+	d.tree = &dirNode{path: ".", name: cmp.Or(d.Root, "/")}
+	d.selected = d.tree
+	n := d.tree
+	if dir := path.Clean(d.Dir); dir != "." {
+		for _, name := range strings.Split(dir, "/") {
+			d.expand(n)
+			i := slices.IndexFunc(n.children, func(c *dirNode) bool { return c.name == name })
+			if i < 0 {
+				break
+			}
+			n = n.children[i]
+		}
+	}
+	d.expand(n)
+	d.refresh()
+	d.selectNode(n)
+}
+
+func (d *directoryDialog) initialFocus() event.Tag { return d }
+
+// load reads the subdirectories of n.
+func (d *directoryDialog) load(n *dirNode) {
+	n.loaded, n.children = true, nil
+	des, err := fs.ReadDir(d.FS, n.path)
+	n.err = err
+	for _, de := range des {
+		name := de.Name()
+		if !d.ShowHidden && strings.HasPrefix(name, ".") {
+			continue
+		}
+		p := path.Join(n.path, name)
+		isDir := de.IsDir()
+		// Follow symbolic links to directories
+		if de.Type()&fs.ModeSymlink != 0 {
+			if info, err := fs.Stat(d.FS, p); err == nil {
+				isDir = info.IsDir()
+			}
+		}
+		if isDir {
+			n.children = append(n.children, &dirNode{path: p, name: name, depth: n.depth + 1, parent: n})
+		}
+	}
+	slices.SortFunc(n.children, func(a, b *dirNode) int {
+		return cmp.Or(cmp.Compare(strings.ToLower(a.name), strings.ToLower(b.name)), cmp.Compare(a.name, b.name))
+	})
+}
+
+// expand shows the children of n, reading them first if necessary.
+func (d *directoryDialog) expand(n *dirNode) {
+	if !n.loaded {
+		d.load(n)
+	}
+	n.expanded = true
+}
+
+// refresh collects the visible nodes.
+func (d *directoryDialog) refresh() {
+	d.visible = d.visible[:0]
+	var walk func(n *dirNode)
+	walk = func(n *dirNode) {
+		d.visible = append(d.visible, n)
+		if n.expanded {
+			for _, c := range n.children {
+				walk(c)
+			}
+		}
+	}
+	walk(d.tree)
+}
+
+// selectNode selects n, validates it and scrolls it into view.
+func (d *directoryDialog) selectNode(n *dirNode) {
+	d.selected = n
+	d.validate()
+	if i := slices.Index(d.visible, n); i >= 0 {
+		if i < d.list.Position.First {
+			d.list.Position.First, d.list.Position.Offset = i, 0
+		} else if i >= d.list.Position.First+d.list.Position.Count {
+			d.list.Position.First = i - max(d.list.Position.Count-1, 0)
+			d.list.Position.Offset = 0
+		}
+	}
+}
+
+// validate runs the optional Validate function against the selected
+// directory, after checking that it could be read.
+func (d *directoryDialog) validate() {
+	d.validationErr = d.selected.err
+	if d.validationErr == nil && d.Validate != nil {
+		d.validationErr = d.Validate(d.selected.path)
+	}
+}
+
+// update processes clicks on the tree, the keyboard navigation and the
+// "New folder" action.
+func (d *directoryDialog) update(gtx layout.Context) bool {
+	changed := false
+	for _, n := range d.visible {
+		if n.toggle.Clicked(gtx) {
+			d.countdown.pause()
+			if n.expanded {
+				n.expanded = false
+			} else {
+				d.expand(n)
+			}
+			changed = true
+		}
+		if n.row.Clicked(gtx) {
+			d.countdown.pause()
+			d.selectNode(n)
+			gtx.Execute(key.FocusCmd{Tag: d})
+		}
+	}
+	if changed {
+		d.refresh()
+	}
+
+	for {
+		ev, ok := gtx.Event(
+			key.FocusFilter{Target: d},
+			key.Filter{Focus: d, Name: key.NameUpArrow},
+			key.Filter{Focus: d, Name: key.NameDownArrow},
+			key.Filter{Focus: d, Name: key.NameLeftArrow},
+			key.Filter{Focus: d, Name: key.NameRightArrow},
+			key.Filter{Focus: d, Name: key.NameHome},
+			key.Filter{Focus: d, Name: key.NameEnd},
+		)
+		if !ok {
+			break
+		}
+		if e, ok := ev.(key.Event); ok && e.State == key.Press {
+			d.countdown.pause()
+			d.navigate(e.Name)
+		}
+	}
+
+	if fsys, ok := d.FS.(MkdirFS); ok && d.newFolderButton.Clicked(gtx) {
+		parent := d.selected
+		d.askNewFolder(fsys, parent.path, func(dir string, err error) {
+			if err != nil {
+				d.validationErr = err
+				return
+			}
+			d.load(parent)
+			d.expand(parent)
+			d.refresh()
+			if i := slices.IndexFunc(parent.children, func(c *dirNode) bool { return c.path == dir }); i >= 0 {
+				d.selectNode(parent.children[i])
+			}
+		})
+	}
+	return false
+}
+
+// navigate moves the selection like in a file manager: Left collapses the
+// selected directory or moves to its parent, Right expands it or moves to
+// its first child.
+func (d *directoryDialog) navigate(name key.Name) {
+	n := d.selected
+	i := slices.Index(d.visible, n)
+	switch name {
+	case key.NameUpArrow:
+		i = max(i-1, 0)
+	case key.NameDownArrow:
+		i = min(i+1, len(d.visible)-1)
+	case key.NameHome:
+		i = 0
+	case key.NameEnd:
+		i = len(d.visible) - 1
+	case key.NameLeftArrow:
+		if n.expanded {
+			n.expanded = false
+			d.refresh()
+		} else if n.parent != nil {
+			d.selectNode(n.parent)
+		}
+		return
+	case key.NameRightArrow:
+		if !n.expanded {
+			d.expand(n)
+			d.refresh()
+		} else if len(n.children) > 0 {
+			d.selectNode(n.children[0])
+		}
+		return
+	}
+	d.selectNode(d.visible[i])
+}
+
+func (d *directoryDialog) layoutBody(gtx layout.Context, th *material.Theme) layout.Dimensions {
+	_, mkdir := d.FS.(MkdirFS)
+	return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+		// Directory tree
+		layout.Flexed(1, func(gtx layout.Context) layout.Dimensions {
+			// Outline the tree while it has the keyboard focus
+			border := widget.Border{Color: th.Fg, Width: unit.Dp(1), CornerRadius: unit.Dp(4)}
+			border.Color.A = 0x40
+			if gtx.Focused(d) {
+				border.Color = th.Palette.ContrastBg
+			}
+			dims := border.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+				gtx.Constraints.Min = gtx.Constraints.Max
+				return material.List(th, &d.list).Layout(gtx, len(d.visible), func(gtx layout.Context, i int) layout.Dimensions {
+					return d.layoutNode(gtx, th, d.visible[i])
+				})
+			})
+			defer clip.Rect{Max: dims.Size}.Push(gtx.Ops).Pop()
+			event.Op(gtx.Ops, d)
+			return dims
+		}),
+		// New folder action and validation status
+		layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+			return layout.Flex{Axis: layout.Horizontal, Alignment: layout.Middle}.Layout(gtx,
+				layout.Flexed(1, func(gtx layout.Context) layout.Dimensions {
+					if d.validationErr == nil {
+						return layout.Dimensions{Size: gtx.Constraints.Min}
+					}
+					msg := material.Caption(th, d.validationErr.Error())
+					msg.Color = errorColor
+					return msg.Layout(gtx)
+				}),
+				layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+					if !mkdir {
+						return layout.Dimensions{}
+					}
+					return flatButton(gtx, th, &d.newFolderButton, "New folder")
+				}),
+			)
+		}),
+	)
+}
+
+// layoutNode lays out the row of n: an expander, indented by its depth,
+// and its name.
+func (d *directoryDialog) layoutNode(gtx layout.Context, th *material.Theme, n *dirNode) layout.Dimensions {
+	btn := material.ButtonLayout(th, &n.row)
+	btn.CornerRadius = 0
+	fg := th.Fg
+	btn.Background = th.Bg
+	if n == d.selected {
+		btn.Background, fg = th.Palette.ContrastBg, th.Palette.ContrastFg
+	}
+	return btn.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+		return layout.Inset{Left: unit.Dp(16 * float32(n.depth))}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+			return layout.Flex{Axis: layout.Horizontal, Alignment: layout.Middle}.Layout(gtx,
+				layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+					gtx.Constraints.Min.X = gtx.Dp(unit.Dp(28))
+					// Directories known to be empty have nothing to expand
+					if n.loaded && len(n.children) == 0 {
+						return layout.Dimensions{Size: gtx.Constraints.Min}
+					}
+					icon := "▸"
+					if n.expanded {
+						icon = "▾"
+					}
+					return material.Clickable(gtx, &n.toggle, func(gtx layout.Context) layout.Dimensions {
+						return layout.Center.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+							l := material.Body2(th, icon)
+							l.Color = fg
+							return l.Layout(gtx)
+						})
+					})
+				}),
+				layout.Flexed(1, func(gtx layout.Context) layout.Dimensions {
+					return layout.Inset{Top: 6, Bottom: 6, Right: 8}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+						l := material.Body2(th, n.name)
+						l.MaxLines = 1
+						l.Color = fg
+						return l.Layout(gtx)
+					})
+				}),
+			)
+		})
+	})
+}
+
+// canConfirm reports whether the selected directory passed validation.
+func (d *directoryDialog) canConfirm() bool {
+	return d.selected != nil && d.validationErr == nil
+}
+
+// confirm accepts the selected directory, validating it once more in case
+// it changed in the meantime.
+func (d *directoryDialog) confirm() bool {
+	d.validate()
+	if d.validationErr == nil && d.ValidateConfirm != nil {
+		d.validationErr = d.ValidateConfirm(d.selected.path)
+	}
+	if !d.canConfirm() {
+		return false
+	}
+	d.path = d.selected.path
+	if d.Root != "" {
+		d.path = filepath.Join(d.Root, filepath.FromSlash(d.path))
+	}
+	return true
+}
+
+func (d *directoryDialog) result() Result { return Result{Outcome: d.outcome, Value: d.path} }
@@ -0,0 +1,129 @@
+package dialog
+
+import (
+	"errors"
+	"io/fs"
+	"reflect"
+	"testing"
+
+	"gioui.org/io/key"
+)
+
+// visiblePaths returns the paths of the visible nodes of d.
+func visiblePaths(d *directoryDialog) []string {
+	var paths []string
+	for _, n := range d.visible {
+		paths = append(paths, n.path)
+	}
+	return paths
+}
+
+func TestDirectory(t *testing.T) {
+	d := NewDirectoryDialog(0, 0, "", "", "", testFS(), "docs")
+	h := newHarness(t, d)
+
+	// Only the directories down to the initial one are read
+	if want := []string{".", "docs", "docs/img", "src"}; !reflect.DeepEqual(visiblePaths(d), want) {
+		t.Errorf("got %q, want %q", visiblePaths(d), want)
+	}
+	if d.tree.children[1].loaded {
+		t.Error("the children of src were read before it was expanded")
+	}
+
+	h.press(key.NameDownArrow)
+	h.press(key.NameRightArrow)
+	if d.selected.path != "docs/img" || !d.selected.expanded {
+		t.Errorf("got %q, want docs/img expanded", d.selected.path)
+	}
+	h.press(key.NameLeftArrow)
+	h.press(key.NameLeftArrow)
+	h.press(key.NameLeftArrow)
+	if d.selected.path != "docs" || d.selected.expanded {
+		t.Errorf("got %q, want docs collapsed", d.selected.path)
+	}
+	h.press(key.NameEnd)
+	if !h.press(key.NameReturn) {
+		t.Fatal("Enter didn't confirm the selected directory")
+	}
+	if got := d.result(); got.Outcome != Confirmed || got.Value != "src" {
+		t.Errorf("got %+v, want src", got)
+	}
+}
+
+func TestDirectoryValidate(t *testing.T) {
+	errNotEmpty := errors.New("not empty")
+	d := NewDirectoryDialog(0, 0, "", "", "", testFS(), "src")
+	d.Validate = func(dir string) error {
+		if entries, _ := fs.ReadDir(d.FS, dir); len(entries) > 0 {
+			return errNotEmpty
+		}
+		return nil
+	}
+	h := newHarness(t, d)
+
+	if d.validationErr != errNotEmpty || h.press(key.NameReturn) {
+		t.Fatal("confirmed a directory that didn't pass validation")
+	}
+}
+
+func TestDirectoryValidateConfirm(t *testing.T) {
+	errReadOnly := errors.New("read-only")
+	var checked []string
+	d := NewDirectoryDialog(0, 0, "", "", "", testFS(), "docs")
+	d.ValidateConfirm = func(dir string) error {
+		checked = append(checked, dir)
+		if dir == "docs" {
+			return errReadOnly
+		}
+		return nil
+	}
+	h := newHarness(t, d)
+
+	h.press(key.NameDownArrow)
+	h.press(key.NameUpArrow)
+	if len(checked) > 0 {
+		t.Fatalf("checked %q before confirming", checked)
+	}
+	if h.press(key.NameReturn) {
+		t.Fatal("confirmed a directory that didn't pass validation")
+	}
+	if d.validationErr != errReadOnly {
+		t.Errorf("error = %v, want %v", d.validationErr, errReadOnly)
+	}
+
+	// Selecting another directory clears the error until it is confirmed.
+	h.press(key.NameEnd)
+	if d.validationErr != nil || !d.canConfirm() {
+		t.Errorf("error = %v after selecting another directory", d.validationErr)
+	}
+	if !h.press(key.NameReturn) {
+		t.Fatal("Enter didn't confirm the selected directory")
+	}
+	if want := []string{"docs", "src"}; !reflect.DeepEqual(checked, want) {
+		t.Errorf("checked %q, want %q", checked, want)
+	}
+}
+
+func TestDirectoryNewFolder(t *testing.T) {
+	fsys := mkdirFS{testFS()}
+	d := NewDirectoryDialog(0, 0, "", "", "", fsys, "docs")
+	h := newHarness(t, d)
+
+	h.router.Source().Execute(key.FocusCmd{Tag: &d.newFolderButton})
+	h.frame()
+	h.press(key.NameSpace)
+	h.frame()
+	h.typeText("drafts")
+	h.press(key.NameReturn)
+	if d.selected.path != "docs/drafts" {
+		t.Fatalf("got %q, want the new folder selected", d.selected.path)
+	}
+	// The tree has the focus again
+	h.frame()
+	if !h.press(key.NameReturn) {
+		t.Fatal("Enter didn't confirm the new folder")
+	}
+	if got := d.result().Value; got != "docs/drafts" {
+		t.Errorf("got %q, want docs/drafts", got)
+	}
+}
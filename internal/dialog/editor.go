@@ -0,0 +1,120 @@
+package dialog
+
+import (
+	"image"
+	"image/color"
+	"io"
+	"unsafe"
+
+	"gioui.org/layout"
+	"gioui.org/op/clip"
+	"gioui.org/op/paint"
+	"gioui.org/unit"
+	"gioui.org/widget"
+	"gioui.org/widget/material"
+)
+
+// styledEditor lays out e with border styling inspired by cu theme. The
+// hint is shown while e is empty.
+func styledEditor(gtx layout.Context, th *material.Theme, e *widget.Editor, hint string) layout.Dimensions {
+	return styledField(gtx, gtx.Focused(e), func(gtx layout.Context) layout.Dimensions {
+		editor := material.Editor(th, e, hint)
+		editor.TextSize = unit.Sp(14)
+		return editor.Layout(gtx)
+	})
+}
+
+// styledField lays out content with the border styling of styledEditor,
+// e.g. for widgets that look like an editor.
+func styledField(gtx layout.Context, focused bool, content layout.Widget) layout.Dimensions {
+	cornerRadius := unit.Dp(4)
+	inset := unit.Dp(4)
+
+	// Set minimum size for input field
+	minWidth := unit.Dp(200)
+	minHeight := unit.Dp(32)
+
+	// Apply minimum constraints
+	gtx.Constraints.Min.X = max(gtx.Constraints.Min.X, gtx.Dp(minWidth))
+	gtx.Constraints.Min.Y = max(gtx.Constraints.Min.Y, gtx.Dp(minHeight))
+
+	return layout.Stack{Alignment: layout.W}.Layout(gtx,
+		// Draw the background and border
+		layout.Expanded(func(gtx layout.Context) layout.Dimensions {
+			rect := image.Rectangle{Max: gtx.Constraints.Min}
+			inner := rect.Inset(gtx.Dp(inset))
+
+			// Colors inspired by cu theme
+			backgroundColor := color.NRGBA{R: 255, G: 255, B: 255, A: 255}
+			borderColor := color.NRGBA{R: 200, G: 200, B: 200, A: 255}
+			focusColor := color.NRGBA{R: 0, G: 123, B: 255, A: 255}
+
+			rr := gtx.Dp(cornerRadius)
+
+			// Draw focus border if focused
+			if focused {
+				w := gtx.Dp(2)
+				paint.FillShape(gtx.Ops, focusColor,
+					clip.Stroke{
+						Path:  clip.UniformRRect(rect.Inset(w), rr+w).Path(gtx.Ops),
+						Width: float32(w),
+					}.Op(),
+				)
+			}
+
+			// Draw background
+			shape := clip.UniformRRect(inner, rr)
+			defer shape.Push(gtx.Ops).Pop()
+			paint.Fill(gtx.Ops, backgroundColor)
+
+			// Draw border
+			w := gtx.Dp(1)
+			paint.FillShape(gtx.Ops, borderColor,
+				clip.Stroke{
+					Path:  clip.UniformRRect(inner, rr).Path(gtx.Ops),
+					Width: float32(w),
+				}.Op(),
+			)
+
+			return layout.Dimensions{Size: gtx.Constraints.Min}
+		}),
+
+		// Draw the text editor on top
+		layout.Stacked(func(gtx layout.Context) layout.Dimensions {
+			// Apply minimum constraints to the editor
+			gtx.Constraints.Min.X = gtx.Dp(minWidth)
+			gtx.Constraints.Min.Y = gtx.Dp(minHeight)
+
+			return layout.Inset{
+				Top:    8,
+				Bottom: 8,
+				Left:   12,
+				Right:  12,
+			}.Layout(gtx, content)
+		}),
+	)
+}
+
+// editorBytes returns the content of e. Unlike Text, it doesn't leave a copy
+// in the editor, so that callers can zero secrets after use.
+func editorBytes(e *widget.Editor) []byte {
+	size, _ := e.Seek(0, io.SeekEnd)
+	b := make([]byte, size)
+	e.Seek(0, io.SeekStart)
+	io.ReadFull(e, b)
+	return b
+}
+
+// setInitialText sets the text of e before the dialog is shown. Gio reports
+// the text as changed on the first update of e; the result tells whether
+// such a ChangeEvent follows, which the caller must not take for an edit.
+func setInitialText(e *widget.Editor, s string) bool {
+	e.SetText(s)
+	return s != ""
+}
+
+// setEditorBytes sets the content of e to b without copying it into a
+// string, which couldn't be zeroed. The editor copies b into its buffer.
+func setEditorBytes(e *widget.Editor, b []byte) {
+	e.SetText(unsafe.String(unsafe.SliceData(b), len(b)))
+}
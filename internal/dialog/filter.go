@@ -0,0 +1,156 @@
+package dialog
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// MatchMode defines how the search field of a select dialog matches the
+// choices.
+type MatchMode int
+
+const (
+	// MatchSubstring keeps the choices that contain the query.
+	MatchSubstring MatchMode = iota
+	// MatchFuzzy keeps the choices that contain the characters of the query
+	// in order, but not necessarily adjacent, e.g. "gdm" matches
+	// "gioui-dialog/main.go". The closest matches are listed first.
+	MatchFuzzy
+)
+
+// matcher filters choices by a search query. Matching ignores case.
+type matcher struct {
+	mode MatchMode
+	// folded holds the lower case choices. Folding maps every rune to a
+	// single rune, so rune positions in folded are rune positions in the
+	// original choices.
+	folded []string
+}
+
+func newMatcher(mode MatchMode, choices []string) *matcher {
+	m := &matcher{mode: mode, folded: make([]string, len(choices))}
+	for i, choice := range choices {
+		m.folded[i] = fold(choice)
+	}
+	return m
+}
+
+func fold(s string) string {
+	return strings.Map(unicode.ToLower, s)
+}
+
+// filter returns the indexes of the candidates that match query, in the
+// order they should be listed.
+func (m *matcher) filter(query string, candidates []int) []int {
+	query = fold(query)
+	matches := make([]int, 0, len(candidates))
+	if m.mode != MatchFuzzy {
+		for _, i := range candidates {
+			if strings.Contains(m.folded[i], query) {
+				matches = append(matches, i)
+			}
+		}
+		return matches
+	}
+
+	type scored struct{ index, score int }
+	var ranked []scored
+	for _, i := range candidates {
+		if score, ok := fuzzyMatch(query, m.folded[i]); ok {
+			ranked = append(ranked, scored{i, score})
+		}
+	}
+	// Ties keep the order of the choices, independent of the order of the
+	// candidates.
+	slices.SortFunc(ranked, func(a, b scored) int {
+		return cmp.Or(a.score-b.score, a.index-b.index)
+	})
+	for _, r := range ranked {
+		matches = append(matches, r.index)
+	}
+	return matches
+}
+
+// fuzzyMatch reports whether the runes of query occur in s in order. The
+// score is the number of runes skipped within the shortest match found,
+// i.e. lower is better.
+func fuzzyMatch(query, s string) (score int, ok bool) {
+	start, end, ok := fuzzyBounds(query, s)
+	if !ok {
+		return 0, false
+	}
+	return utf8.RuneCountInString(s[start:end]) - utf8.RuneCountInString(query), true
+}
+
+// fuzzyBounds returns the byte range of s that contains the runes of query.
+// After the first occurrence was found, the range is narrowed by searching
+// backwards from its end, so that "ab" matches the end of "a-ab".
+func fuzzyBounds(query, s string) (start, end int, ok bool) {
+	if query == "" {
+		return 0, 0, true
+	}
+	q := query
+	for i, r := range s {
+		qr, size := utf8.DecodeRuneInString(q)
+		if r != qr {
+			continue
+		}
+		q = q[size:]
+		if q == "" {
+			end = i + utf8.RuneLen(r)
+			break
+		}
+	}
+	if q != "" {
+		return 0, 0, false
+	}
+
+	q = query
+	start = end
+	for q != "" {
+		r, size := utf8.DecodeLastRuneInString(s[:start])
+		start -= size
+		if qr, qsize := utf8.DecodeLastRuneInString(q); r == qr {
+			q = q[:len(q)-qsize]
+		}
+	}
+	return start, end, true
+}
+
+// positions returns the rune positions of the characters of choice i that
+// match query, for highlighting.
+func (m *matcher) positions(query string, i int) []int {
+	query = fold(query)
+	s := m.folded[i]
+	if query == "" {
+		return nil
+	}
+
+	var start, end int
+	if m.mode == MatchFuzzy {
+		var ok bool
+		if start, end, ok = fuzzyBounds(query, s); !ok {
+			return nil
+		}
+	} else {
+		if start = strings.Index(s, query); start < 0 {
+			return nil
+		}
+		end = start + len(query)
+	}
+
+	var pos []int
+	q := query
+	n := utf8.RuneCountInString(s[:start])
+	for _, r := range s[start:end] {
+		if qr, size := utf8.DecodeRuneInString(q); r == qr {
+			pos = append(pos, n)
+			q = q[size:]
+		}
+		n++
+	}
+	return pos
+}
@@ -0,0 +1,80 @@
+package dialog
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+)
+
+func TestMatcherFilter(t *testing.T) {
+	choices := []string{"main", "feature/login", "fix/Main-menu", "release/1.0", "Ärger"}
+
+	tests := []struct {
+		mode  MatchMode
+		query string
+		want  []int
+	}{
+		{MatchSubstring, "main", []int{0, 2}},
+		{MatchSubstring, "MAIN", []int{0, 2}},
+		{MatchSubstring, "/", []int{1, 2, 3}},
+		{MatchSubstring, "fln", nil},
+		{MatchSubstring, "är", []int{4}},
+		{MatchFuzzy, "fln", []int{1}},
+		{MatchFuzzy, "mn", []int{0, 2}},
+		// Only "fix/Main-menu" contains "f", "m" and "m" in this order.
+		{MatchFuzzy, "fmm", []int{2}},
+		{MatchFuzzy, "fi", []int{2, 1}},
+		{MatchFuzzy, "xyz", nil},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%d/%s", tt.mode, tt.query), func(t *testing.T) {
+			m := newMatcher(tt.mode, choices)
+			got := m.filter(tt.query, []int{0, 1, 2, 3, 4})
+			if !slices.Equal(got, tt.want) && len(got)+len(tt.want) > 0 {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatcherPositions(t *testing.T) {
+	tests := []struct {
+		mode   MatchMode
+		query  string
+		choice string
+		want   []int
+	}{
+		{MatchSubstring, "in", "Main", []int{2, 3}},
+		{MatchSubstring, "RG", "Ärger", []int{1, 2}},
+		{MatchFuzzy, "mn", "Main", []int{0, 3}},
+		// The shortest match is highlighted, not the first one.
+		{MatchFuzzy, "ab", "a-ab", []int{2, 3}},
+		{MatchFuzzy, "äe", "Ärger", []int{0, 3}},
+		{MatchFuzzy, "x", "Main", nil},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%d/%s", tt.mode, tt.query), func(t *testing.T) {
+			m := newMatcher(tt.mode, []string{tt.choice})
+			if got := m.positions(tt.query, 0); !slices.Equal(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func BenchmarkMatcherFilter(b *testing.B) {
+	choices := make([]string, 50000)
+	all := make([]int, len(choices))
+	for i := range choices {
+		choices[i] = fmt.Sprintf("github.com/example/package-%05d/internal/module", i)
+		all[i] = i
+	}
+	for _, mode := range []MatchMode{MatchSubstring, MatchFuzzy} {
+		m := newMatcher(mode, choices)
+		b.Run(fmt.Sprint(mode), func(b *testing.B) {
+			for b.Loop() {
+				m.filter("pkg42mod", all)
+			}
+		})
+	}
+}
@@ -0,0 +1,427 @@
+package dialog
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"gioui.org/io/event"
+	"gioui.org/io/key"
+	"gioui.org/layout"
+	"gioui.org/unit"
+	"gioui.org/widget"
+	"gioui.org/widget/material"
+)
+
+// FieldKind defines the input widget of a form field, and the type of its
+// value.
+type FieldKind int
+
+const (
+	// FieldText is a single line of text. Its value is a string.
+	FieldText FieldKind = iota
+	// FieldPassword is a masked single line of text. Its value is a
+	// []byte, like the secret of a password dialog.
+	FieldPassword
+	// FieldNumber is a decimal number. Its value is a float64, or nil
+	// while the field is empty.
+	FieldNumber
+	// FieldCheckbox is a checkbox. Its value is a bool.
+	FieldCheckbox
+	// FieldCombo is a choice of Choices, picked from a list. Its value is
+	// a string.
+	FieldCombo
+	// FieldDate is a date entered as YYYY-MM-DD. Its value is a
+	// time.Time in UTC, or nil while the field is empty.
+	FieldDate
+	// FieldMultiline is text spanning several lines. Its value is a
+	// string.
+	FieldMultiline
+)
+
+// FormField describes a field of a form dialog.
+type FormField struct {
+	// Name is the key of the value in the result.
+	Name  string
+	Label string
+	Kind  FieldKind
+	// Default is the initial value: a bool for checkboxes, a time.Time for
+	// dates, and a string or a number otherwise. Without it, a combo
+	// starts with its first choice.
+	Default any
+	// Choices are the options of a combo.
+	Choices []string
+	// Validate checks the value of the field. The dialog can't be
+	// confirmed while it returns an error, which is shown below the
+	// field. The value of a password is zeroed after the check, unless
+	// the form is confirmed with it.
+	Validate func(value any) error
+}
+
+// formField is a FormField and the state of its widget.
+type formField struct {
+	FormField
+
+	editor widget.Editor
+	check  widget.Bool
+	combo  widget.Clickable
+	choice string
+	err    error
+	// preset tells update to ignore the change of the default value.
+	preset bool
+}
+
+// formDialog is the internal implementation of a form dialog: a list of
+// labeled fields of different kinds, each validated on its own, and all of
+// them together.
+type formDialog struct {
+	BaseDialog
+	// Validate checks the values of all fields once each of them is
+	// valid. The dialog can't be confirmed while it returns an error,
+	// which is shown below the fields.
+	Validate func(values map[string]any) error
+
+	// internal result state
+	values map[string]any
+
+	fields  []*formField
+	formErr error
+
+	// UI state
+	list widget.List
+	// refocus is focused instead of the first field once a nested dialog
+	// was closed.
+	refocus event.Tag
+}
+
+// NewFormDialog initializes a formDialog from provided parameters.
+func NewFormDialog(width, height float32, title, label, description string, fields []FormField) *formDialog {
+	if height <= 0 {
+		height = min(160+56*float32(len(fields)), 640)
+	}
+	d := &formDialog{
+		BaseDialog: *NewBaseDialog(width, height, title, label, description),
+	}
+	for _, field := range fields {
+		f := &formField{FormField: field}
+		switch f.Kind {
+		case FieldCheckbox:
+			f.check.Value, _ = f.Default.(bool)
+		case FieldCombo:
+			f.choice = defaultText(f.Default)
+			if f.Default == nil && len(f.Choices) > 0 {
+				f.choice = f.Choices[0]
+			}
+		case FieldMultiline:
+			f.preset = setInitialText(&f.editor, defaultText(f.Default))
+		default:
+			f.editor.SingleLine = true
+			f.editor.Submit = true
+			if f.Kind == FieldPassword {
+				f.editor.Mask = passwordMask
+			}
+			if secret, ok := f.Default.([]byte); ok {
+				setEditorBytes(&f.editor, secret)
+				f.preset = len(secret) > 0
+			} else {
+				f.preset = setInitialText(&f.editor, defaultText(f.Default))
+			}
+		}
+		d.fields = append(d.fields, f)
+	}
+	d.list.Axis = layout.Vertical
+	return d
+}
+
+// open validates the default values before the dialog is shown.
+func (d *formDialog) open(invalidate func()) {
+	d.BaseDialog.open(invalidate)
+	d.check()
+}
+
+// defaultText returns the text an editor or a combo starts with for the
+// default value v.
+func defaultText(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case time.Time:
+		if v.IsZero() {
+			return ""
+		}
+		return v.Format(time.DateOnly)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// close drops the content of the password fields, including their undo
+// history.
+func (d *formDialog) close() {
+	for _, f := range d.fields {
+		if f.Kind == FieldPassword {
+			f.editor = widget.Editor{}
+		}
+	}
+}
+
+// initialFocus focuses the first field, or the combo that opened the list
+// of its choices.
+func (d *formDialog) initialFocus() event.Tag {
+	if tag := d.refocus; tag != nil {
+		d.refocus = nil
+		return tag
+	}
+	for _, f := range d.fields {
+		switch f.Kind {
+		case FieldCheckbox:
+			return &f.check
+		case FieldCombo:
+			return &f.combo
+		default:
+			return &f.editor
+		}
+	}
+	return nil
+}
+
+// update processes the events of all fields and validates the form when
+// one of them changed. Enter in a single line field or on a checkbox
+// submits the form.
+func (d *formDialog) update(gtx layout.Context) bool {
+	submit, changed := false, false
+	for _, f := range d.fields {
+		switch f.Kind {
+		case FieldCheckbox:
+			// Enter submits the form, instead of toggling the checkbox
+			for {
+				ev, ok := gtx.Event(
+					key.Filter{Focus: &f.check, Name: key.NameReturn},
+					key.Filter{Focus: &f.check, Name: key.NameEnter},
+				)
+				if !ok {
+					break
+				}
+				if e, ok := ev.(key.Event); ok && e.State == key.Press {
+					submit = true
+				}
+			}
+			if f.check.Update(gtx) {
+				changed = true
+			}
+		case FieldCombo:
+			if f.combo.Clicked(gtx) {
+				d.countdown.pause()
+				d.askChoice(f)
+			}
+		default:
+			for {
+				ev, ok := f.editor.Update(gtx)
+				if !ok {
+					break
+				}
+				switch ev.(type) {
+				case widget.ChangeEvent:
+					if f.preset {
+						f.preset = false
+						break
+					}
+					changed = true
+				case widget.SubmitEvent:
+					submit = true
+				}
+			}
+		}
+	}
+	if changed {
+		d.countdown.pause()
+		d.check()
+	}
+	return submit
+}
+
+// askChoice lets the user pick the value of the combo f from a list.
+func (d *formDialog) askChoice(f *formField) {
+	list := NewSelectDialog(d.Width, min(d.Height, 360), f.Label, f.Label, "", f.Choices, f.choice, false)
+	d.ask(list, func(r Result) bool {
+		if r.Outcome == Confirmed {
+			f.choice = r.Value
+			d.check()
+		}
+		d.refocus = &f.combo
+		return false
+	})
+}
+
+// value returns the value of f, or an error if its input can't be parsed.
+func (f *formField) value() (any, error) {
+	switch f.Kind {
+	case FieldPassword:
+		return editorBytes(&f.editor), nil
+	case FieldNumber:
+		text := strings.TrimSpace(f.editor.Text())
+		if text == "" {
+			return nil, nil
+		}
+		n, err := strconv.ParseFloat(text, 64)
+		if err != nil {
+			return nil, errors.New("enter a number")
+		}
+		return n, nil
+	case FieldCheckbox:
+		return f.check.Value, nil
+	case FieldCombo:
+		return f.choice, nil
+	case FieldDate:
+		text := strings.TrimSpace(f.editor.Text())
+		if text == "" {
+			return nil, nil
+		}
+		t, err := time.Parse(time.DateOnly, text)
+		if err != nil {
+			return nil, errors.New("enter a date as YYYY-MM-DD")
+		}
+		return t, nil
+	default:
+		return f.editor.Text(), nil
+	}
+}
+
+// validate runs the validators of the fields, and the one of the form if
+// all fields are valid. It returns the values of the fields.
+func (d *formDialog) validate() map[string]any {
+	values := make(map[string]any, len(d.fields))
+	valid := true
+	for _, f := range d.fields {
+		v, err := f.value()
+		if err == nil && f.Validate != nil {
+			err = f.Validate(v)
+		}
+		f.err = err
+		valid = valid && err == nil
+		values[f.Name] = v
+	}
+	d.formErr = nil
+	if valid && d.Validate != nil {
+		d.formErr = d.Validate(values)
+	}
+	return values
+}
+
+// check validates the fields like validate, and zeroes the copies of the
+// passwords, so that only a confirmed form keeps one.
+func (d *formDialog) check() {
+	clearSecrets(d.validate())
+}
+
+// clearSecrets zeroes the values of password fields.
+func clearSecrets(values map[string]any) {
+	for _, v := range values {
+		if b, ok := v.([]byte); ok {
+			clear(b)
+		}
+	}
+}
+
+func (d *formDialog) layoutBody(gtx layout.Context, th *material.Theme) layout.Dimensions {
+	return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+		// Fields with scrollable list
+		layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+			// Leave room for the label and the buttons
+			maxHeight := unit.Dp(max(d.Height-160, 60))
+			gtx.Constraints.Max.Y = min(gtx.Constraints.Max.Y, gtx.Dp(maxHeight))
+			return material.List(th, &d.list).Layout(gtx, len(d.fields), func(gtx layout.Context, i int) layout.Dimensions {
+				return d.layoutField(gtx, th, d.fields[i])
+			})
+		}),
+		// Cross-field validation
+		layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+			if d.formErr == nil {
+				return layout.Dimensions{}
+			}
+			msg := material.Caption(th, d.formErr.Error())
+			msg.Color = errorColor
+			return msg.Layout(gtx)
+		}),
+	)
+}
+
+// layoutField lays out the label of f next to its widget, and its
+// validation error below.
+func (d *formDialog) layoutField(gtx layout.Context, th *material.Theme, f *formField) layout.Dimensions {
+	return layout.Inset{Bottom: unit.Dp(6)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+		return layout.Flex{Axis: layout.Horizontal, Alignment: layout.Start}.Layout(gtx,
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				width := gtx.Dp(unit.Dp(120))
+				gtx.Constraints.Min.X, gtx.Constraints.Max.X = width, width
+				return layout.Inset{Top: unit.Dp(10), Right: unit.Dp(8)}.Layout(gtx, material.Body1(th, f.Label).Layout)
+			}),
+			layout.Flexed(1, func(gtx layout.Context) layout.Dimensions {
+				return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+					layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+						return d.layoutWidget(gtx, th, f)
+					}),
+					layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+						if f.err == nil {
+							return layout.Dimensions{}
+						}
+						msg := material.Caption(th, f.err.Error())
+						msg.Color = errorColor
+						return msg.Layout(gtx)
+					}),
+				)
+			}),
+		)
+	})
+}
+
+// layoutWidget lays out the input widget of f.
+func (d *formDialog) layoutWidget(gtx layout.Context, th *material.Theme, f *formField) layout.Dimensions {
+	switch f.Kind {
+	case FieldCheckbox:
+		return material.CheckBox(th, &f.check, "").Layout(gtx)
+	case FieldCombo:
+		return f.combo.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+			return styledField(gtx, gtx.Focused(&f.combo), func(gtx layout.Context) layout.Dimensions {
+				l := material.Body2(th, f.choice+" ▾")
+				l.MaxLines = 1
+				return l.Layout(gtx)
+			})
+		})
+	case FieldMultiline:
+		gtx.Constraints.Min.Y = gtx.Dp(unit.Dp(80))
+		gtx.Constraints.Max.Y = max(gtx.Constraints.Min.Y, gtx.Dp(unit.Dp(120)))
+		return styledEditor(gtx, th, &f.editor, "")
+	case FieldDate:
+		return styledEditor(gtx, th, &f.editor, "YYYY-MM-DD")
+	default:
+		return styledEditor(gtx, th, &f.editor, "")
+	}
+}
+
+// canConfirm reports whether all fields and the form are valid.
+func (d *formDialog) canConfirm() bool {
+	for _, f := range d.fields {
+		if f.err != nil {
+			return false
+		}
+	}
+	return d.formErr == nil
+}
+
+// confirm accepts the values if all fields and the form are valid.
+func (d *formDialog) confirm() bool {
+	values := d.validate()
+	if !d.canConfirm() {
+		clearSecrets(values)
+		return false
+	}
+	d.values = values
+	return true
+}
+
+func (d *formDialog) result() Result { return Result{Outcome: d.outcome, Fields: d.values} }
@@ -0,0 +1,146 @@
+package dialog
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"gioui.org/io/key"
+)
+
+func TestForm(t *testing.T) {
+	d := NewFormDialog(0, 0, "", "", "", []FormField{
+		{Name: "host", Label: "Host", Default: "example"},
+		{Name: "port", Label: "Port", Kind: FieldNumber, Default: 22},
+		{Name: "password", Label: "Password", Kind: FieldPassword, Default: []byte("s3cret")},
+		{Name: "remember", Label: "Remember", Kind: FieldCheckbox, Default: true},
+		{Name: "level", Label: "Level", Kind: FieldCombo, Choices: []string{"low", "high"}},
+		{Name: "due", Label: "Due", Kind: FieldDate, Default: time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC)},
+		{Name: "notes", Label: "Notes", Kind: FieldMultiline, Default: "a\nb"},
+	})
+	h := newHarness(t, d)
+
+	h.typeText(".org")
+	if !h.press(key.NameReturn) {
+		t.Fatal("Enter didn't confirm the form")
+	}
+	got := result(d)
+	want := map[string]any{
+		"host":     "example.org",
+		"port":     22.0,
+		"password": []byte("s3cret"),
+		"remember": true,
+		"level":    "low",
+		"due":      time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC),
+		"notes":    "a\nb",
+	}
+	if got.Outcome != Confirmed || !reflect.DeepEqual(got.Fields, want) {
+		t.Errorf("got %s %v, want %v", got.Outcome, got.Fields, want)
+	}
+}
+
+func TestFormValidation(t *testing.T) {
+	errPrivileged := errors.New("choose a port above 1023")
+	d := NewFormDialog(0, 0, "", "", "", []FormField{
+		{Name: "port", Label: "Port", Kind: FieldNumber, Validate: func(v any) error {
+			if n, ok := v.(float64); ok && n < 1024 {
+				return errPrivileged
+			}
+			return nil
+		}},
+		{Name: "due", Label: "Due", Kind: FieldDate},
+	})
+	errMissing := errors.New("enter a port or a date")
+	d.Validate = func(values map[string]any) error {
+		if values["port"] == nil && values["due"] == nil {
+			return errMissing
+		}
+		return nil
+	}
+	h := newHarness(t, d)
+
+	if d.formErr != errMissing || h.press(key.NameReturn) {
+		t.Fatal("confirmed a form that didn't pass the form validator")
+	}
+	h.typeText("80")
+	if d.fields[0].err != errPrivileged || h.press(key.NameReturn) {
+		t.Fatal("confirmed a field that didn't pass its validator")
+	}
+	h.typeText("80")
+	if d.fields[0].err != nil || d.formErr != nil {
+		t.Fatalf("got %v, %v for a valid form", d.fields[0].err, d.formErr)
+	}
+	h.router.Source().Execute(key.FocusCmd{Tag: &d.fields[1].editor})
+	h.frame()
+	h.typeText("soon")
+	if d.fields[1].err == nil || h.press(key.NameReturn) {
+		t.Fatal("confirmed a date that can't be parsed")
+	}
+}
+
+func TestFormPasswordCopies(t *testing.T) {
+	var checked [][]byte
+	d := NewFormDialog(0, 0, "", "", "", []FormField{
+		{Name: "password", Label: "Password", Kind: FieldPassword, Validate: func(v any) error {
+			checked = append(checked, v.([]byte))
+			return nil
+		}},
+	})
+	h := newHarness(t, d)
+
+	h.typeText("s3")
+	h.typeText("cret")
+	if len(checked) < 3 {
+		t.Fatalf("validated %d times, want on open and every change", len(checked))
+	}
+	for _, b := range checked {
+		if !bytes.Equal(b, make([]byte, len(b))) {
+			t.Errorf("copy %q of the password wasn't zeroed after validation", b)
+		}
+	}
+	if !h.press(key.NameReturn) {
+		t.Fatal("Enter didn't confirm the form")
+	}
+	if got := result(d).Fields["password"]; !reflect.DeepEqual(got, []byte("s3cret")) {
+		t.Errorf("got password %q, want the confirmed one", got)
+	}
+}
+
+func TestFormCombo(t *testing.T) {
+	d := NewFormDialog(0, 0, "", "", "", []FormField{
+		{Name: "level", Label: "Level", Kind: FieldCombo, Default: "medium", Choices: []string{"low", "medium", "high"}},
+		{Name: "agree", Label: "Agree", Kind: FieldCheckbox},
+	})
+	h := newHarness(t, d)
+
+	h.press(key.NameSpace)
+	if d.nested == nil {
+		t.Fatal("the combo didn't show its choices")
+	}
+	// The first frame focuses the list of the choices
+	h.frame()
+	h.press(key.NameDownArrow)
+	if h.press(key.NameReturn) {
+		t.Fatal("picking a choice closed the form")
+	}
+	if d.fields[0].choice != "high" {
+		t.Errorf("got %q, want high", d.fields[0].choice)
+	}
+
+	// The combo has the focus again
+	h.frame()
+	if !h.router.Source().Focused(&d.fields[0].combo) {
+		t.Error("the combo lost the focus")
+	}
+	h.router.Source().Execute(key.FocusCmd{Tag: &d.fields[1].check})
+	h.frame()
+	h.press(key.NameSpace)
+	if !h.press(key.NameReturn) {
+		t.Fatal("Enter didn't confirm the form")
+	}
+	if got := result(d).Fields; got["level"] != "high" || got["agree"] != true {
+		t.Errorf("got %v, want high and agreed", got)
+	}
+}
@@ -0,0 +1,92 @@
+package dialog
+
+import (
+	"image"
+	"image/color"
+	"math"
+
+	"gioui.org/f32"
+	"gioui.org/layout"
+	"gioui.org/op"
+	"gioui.org/op/clip"
+	"gioui.org/op/paint"
+)
+
+var (
+	infoColor    = color.NRGBA{R: 25, G: 118, B: 210, A: 255}
+	warningColor = color.NRGBA{R: 249, G: 168, B: 37, A: 255}
+	glyphLight   = color.NRGBA{R: 255, G: 255, B: 255, A: 255}
+	glyphDark    = color.NRGBA{R: 33, G: 33, B: 33, A: 255}
+)
+
+// layoutSeverityIcon draws the icon of severity s, filling a square of
+// size pixels. The icons are drawn with vector operations, so they scale
+// without loss.
+func layoutSeverityIcon(gtx layout.Context, s Severity, size int) layout.Dimensions {
+	sz := float32(size)
+	// pt converts coordinates relative to the icon size.
+	pt := func(x, y float32) f32.Point { return f32.Pt(x*sz, y*sz) }
+
+	switch s {
+	case SeverityWarning:
+		var p clip.Path
+		p.Begin(gtx.Ops)
+		p.MoveTo(pt(0.5, 0.06))
+		p.LineTo(pt(0.96, 0.9))
+		p.LineTo(pt(0.04, 0.9))
+		p.Close()
+		paint.FillShape(gtx.Ops, warningColor, clip.Outline{Path: p.End()}.Op())
+		fillRect(gtx.Ops, pt(0.45, 0.34), pt(0.55, 0.64), glyphDark)
+		fillCircle(gtx.Ops, pt(0.5, 0.76), 0.06*sz, glyphDark)
+
+	case SeverityError:
+		fillCircle(gtx.Ops, pt(0.5, 0.5), 0.5*sz, errorColor)
+		strokeLine(gtx.Ops, 0.1*sz, glyphLight, pt(0.32, 0.32), pt(0.68, 0.68))
+		strokeLine(gtx.Ops, 0.1*sz, glyphLight, pt(0.68, 0.32), pt(0.32, 0.68))
+
+	case SeverityQuestion:
+		fillCircle(gtx.Ops, pt(0.5, 0.5), 0.5*sz, infoColor)
+		// The hook of the question mark: an arc from the left over the
+		// top, continued by the stem.
+		var hook []f32.Point
+		for a := math.Pi; a <= 2.4*math.Pi; a += math.Pi / 12 {
+			hook = append(hook, pt(0.5+0.15*float32(math.Cos(a)), 0.36+0.15*float32(math.Sin(a))))
+		}
+		hook = append(hook, pt(0.5, 0.54), pt(0.5, 0.62))
+		strokeLine(gtx.Ops, 0.09*sz, glyphLight, hook...)
+		fillCircle(gtx.Ops, pt(0.5, 0.76), 0.06*sz, glyphLight)
+
+	default:
+		fillCircle(gtx.Ops, pt(0.5, 0.5), 0.5*sz, infoColor)
+		fillCircle(gtx.Ops, pt(0.5, 0.27), 0.07*sz, glyphLight)
+		fillRect(gtx.Ops, pt(0.44, 0.41), pt(0.56, 0.77), glyphLight)
+	}
+	return layout.Dimensions{Size: image.Pt(size, size)}
+}
+
+func fillCircle(ops *op.Ops, center f32.Point, radius float32, c color.NRGBA) {
+	r := image.Rectangle{
+		Min: image.Pt(int(center.X-radius+0.5), int(center.Y-radius+0.5)),
+		Max: image.Pt(int(center.X+radius+0.5), int(center.Y+radius+0.5)),
+	}
+	paint.FillShape(ops, c, clip.Ellipse(r).Op(ops))
+}
+
+func fillRect(ops *op.Ops, min, max f32.Point, c color.NRGBA) {
+	r := image.Rectangle{
+		Min: image.Pt(int(min.X+0.5), int(min.Y+0.5)),
+		Max: image.Pt(int(max.X+0.5), int(max.Y+0.5)),
+	}
+	paint.FillShape(ops, c, clip.Rect(r).Op())
+}
+
+// strokeLine draws a line through points.
+func strokeLine(ops *op.Ops, width float32, c color.NRGBA, points ...f32.Point) {
+	var p clip.Path
+	p.Begin(ops)
+	p.MoveTo(points[0])
+	for _, pt := range points[1:] {
+		p.LineTo(pt)
+	}
+	paint.FillShape(ops, c, clip.Stroke{Path: p.End(), Width: width}.Op())
+}
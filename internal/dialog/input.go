@@ -1,249 +1,183 @@
 package dialog
 
 import (
+	"context"
 	"image"
 	"image/color"
-	"sync"
 	"time"
 
-	"gioui.org/app"
-	"gioui.org/io/key"
-	"gioui.org/io/system"
+	"gioui.org/io/event"
 	"gioui.org/layout"
-	"gioui.org/op"
-	"gioui.org/op/clip"
-	"gioui.org/op/paint"
 	"gioui.org/unit"
 	"gioui.org/widget"
 	"gioui.org/widget/material"
 )
 
+// errorColor is used to display validation errors.
+var errorColor = color.NRGBA{R: 211, G: 47, B: 47, A: 255}
+
 // inputDialog is the internal implementation stub for a text-input dialog.
 type inputDialog struct {
-	Width, Height float32
-	Title         string
-	Label         string
-	Description   string
-	DefaultText   string
-	Validate      func(string) error
+	BaseDialog
+	DefaultText string
+	Validate    func(string) error
+
+	// Live validation, run debounced while the user is typing.
+	ValidateLive  func(string) error
+	ValidateAsync func(context.Context, string) error
+	ValidateDelay time.Duration
 
 	// internal result state
-	result   string
-	canceled bool
+	value string
+
+	// validationErr holds the error returned by Validate for the current
+	// editor content, or nil if the content is valid.
+	validationErr error
+	live          liveValidator
+	// validated reports whether the input was validated at all. The input
+	// is only validated once the user changes or confirms it, so that no
+	// error shows up before the user had a chance to type.
+	validated bool
 
 	// UI state
-	textInput    widget.Editor
-	okButton     widget.Clickable
-	cancelButton widget.Clickable
-	done         bool
+	textInput widget.Editor
+	// preset tells update to ignore the change of the default text.
+	preset bool
 }
 
 // NewInputDialog initializes an inputDialog from provided parameters.
 func NewInputDialog(width, height float32, title, label, description, defaultText string, validate func(string) error) *inputDialog {
-	if width <= 0 {
-		width = 400
-	}
 	if height <= 0 {
 		height = 200
 	}
 	d := &inputDialog{
-		Width:       width,
-		Height:      height,
-		Title:       title,
-		Label:       label,
-		Description: description,
+		BaseDialog:  *NewBaseDialog(width, height, title, label, description),
 		DefaultText: defaultText,
 		Validate:    validate,
 	}
 	// Initialize text input with default text
-	d.textInput.SetText(defaultText)
+	d.preset = setInitialText(&d.textInput, defaultText)
 	d.textInput.SingleLine = true
+	d.textInput.Submit = true
 	return d
 }
 
-// styledEditor creates an editor with border styling inspired by cu theme
-func (d *inputDialog) styledEditor(gtx layout.Context, th *material.Theme) layout.Dimensions {
-	cornerRadius := unit.Dp(4)
-	inset := unit.Dp(4)
-
-	// Set minimum size for input field
-	minWidth := unit.Dp(200)
-	minHeight := unit.Dp(32)
-
-	// Apply minimum constraints
-	gtx.Constraints.Min.X = max(gtx.Constraints.Min.X, gtx.Dp(minWidth))
-	gtx.Constraints.Min.Y = max(gtx.Constraints.Min.Y, gtx.Dp(minHeight))
-
-	return layout.Stack{Alignment: layout.W}.Layout(gtx,
-		// Draw the background and border
-		layout.Expanded(func(gtx layout.Context) layout.Dimensions {
-			rect := image.Rectangle{Max: gtx.Constraints.Min}
-			inner := rect.Inset(gtx.Dp(inset))
-
-			// Colors inspired by cu theme
-			backgroundColor := color.NRGBA{R: 255, G: 255, B: 255, A: 255}
-			borderColor := color.NRGBA{R: 200, G: 200, B: 200, A: 255}
-			focusColor := color.NRGBA{R: 0, G: 123, B: 255, A: 255}
-
-			rr := gtx.Dp(cornerRadius)
-
-			// Draw focus border if focused
-			if gtx.Focused(&d.textInput) {
-				w := gtx.Dp(2)
-				paint.FillShape(gtx.Ops, focusColor,
-					clip.Stroke{
-						Path:  clip.UniformRRect(rect.Inset(w), rr+w).Path(gtx.Ops),
-						Width: float32(w),
-					}.Op(),
-				)
-			}
+// open prepares the timeout and live validation before the dialog is shown.
+func (d *inputDialog) open(invalidate func()) {
+	d.BaseDialog.open(invalidate)
+	d.live.validate = d.ValidateLive
+	d.live.validateAsync = d.ValidateAsync
+	d.live.delay = d.ValidateDelay
+	d.live.invalidate = invalidate
+}
 
-			// Draw background
-			shape := clip.UniformRRect(inner, rr)
-			defer shape.Push(gtx.Ops).Pop()
-			paint.Fill(gtx.Ops, backgroundColor)
-
-			// Draw border
-			w := gtx.Dp(1)
-			paint.FillShape(gtx.Ops, borderColor,
-				clip.Stroke{
-					Path:  clip.UniformRRect(inner, rr).Path(gtx.Ops),
-					Width: float32(w),
-				}.Op(),
-			)
+// close stops any pending validation once the dialog is gone.
+func (d *inputDialog) close() {
+	d.live.stop()
+}
 
-			return layout.Dimensions{Size: gtx.Constraints.Min}
-		}),
+func (d *inputDialog) initialFocus() event.Tag { return &d.textInput }
+
+// update processes editor events and re-validates the input whenever
+// its content changes. Enter in the editor submits the input.
+func (d *inputDialog) update(gtx layout.Context) bool {
+	submit := false
+	for {
+		ev, ok := d.textInput.Update(gtx)
+		if !ok {
+			break
+		}
+		switch ev.(type) {
+		case widget.ChangeEvent:
+			if d.preset {
+				d.preset = false
+				break
+			}
+			d.countdown.pause()
+			d.validated = true
+			d.validate()
+			d.live.schedule(d.textInput.Text(), gtx.Now)
+		case widget.SubmitEvent:
+			submit = true
+		}
+	}
+	d.live.update(gtx)
+	return submit
+}
 
-		// Draw the text editor on top
-		layout.Stacked(func(gtx layout.Context) layout.Dimensions {
-			// Apply minimum constraints to the editor
-			gtx.Constraints.Min.X = gtx.Dp(minWidth)
-			gtx.Constraints.Min.Y = gtx.Dp(minHeight)
-
-			return layout.Inset{
-				Top:    8,
-				Bottom: 8,
-				Left:   12,
-				Right:  12,
-			}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
-				editor := material.Editor(th, &d.textInput, "")
-				editor.TextSize = unit.Sp(14)
-				return editor.Layout(gtx)
-			})
+func (d *inputDialog) layoutBody(gtx layout.Context, th *material.Theme) layout.Dimensions {
+	return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+		// Text input
+		layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+			return styledEditor(gtx, th, &d.textInput, "")
+		}),
+		// Validation status
+		layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+			return d.validationStatus(gtx, th)
 		}),
 	)
 }
 
-// Show runs the text-input dialog event loop and returns the entered text,
-// a canceled flag, and an error if something went wrong.
-func (d *inputDialog) Show() (string, bool, error) {
-	w := app.Window{}
-	w.Option(
-		app.Title(d.Title),
-		app.Size(unit.Dp(d.Width), unit.Dp(d.Height)),
-	)
-	// TODO work around https://todo.sr.ht/~eliasnaur/gio/602 (still an issue in gio v0.8.0?)
-	// this should only be required shortly after creating the window w.
-	// It doesn't work with the current gio version (0.8.1-dev), which only includes a fix for os_windows.
-	applyWindowOptions := sync.OnceFunc(func() {
-		time.Sleep(50 * time.Millisecond)
-		w.Perform(system.ActionCenter | system.ActionRaise)
-	})
-	w.Perform(system.ActionCenter | system.ActionRaise)
-
-	th := material.NewTheme()
-	var ops op.Ops
-
-	for !d.done {
-		switch e := w.Event().(type) {
-		case app.FrameEvent:
-			applyWindowOptions()
-			gtx := app.NewContext(&ops, e)
-			if d.cancelButton.Clicked(gtx) {
-				d.handleCancel()
-				w.Perform(system.ActionClose)
-			}
-			if d.okButton.Clicked(gtx) {
-				d.handleOK()
-				w.Perform(system.ActionClose)
-			}
-			d.layout(gtx, th)
-			e.Frame(gtx.Ops)
-		case key.Event:
-			// Handle Escape key for cancel
-			if e.Name == key.NameEscape && e.State == key.Press {
-				d.handleCancel()
-				w.Perform(system.ActionClose)
-			}
-			// Handle Enter key for OK
-			if e.Name == key.NameReturn && e.State == key.Press {
-				d.handleOK()
-				w.Perform(system.ActionClose)
-			}
-		case app.DestroyEvent:
-			d.done = true
-			return d.result, d.canceled, e.Err
+// canConfirm reports whether the current input passed all validators.
+func (d *inputDialog) canConfirm() bool {
+	return d.validationErr == nil && !d.live.busy() && d.live.result() == nil
+}
+
+// validationStatus shows the validation error for the current input, or a
+// spinner while an asynchronous validation is running.
+func (d *inputDialog) validationStatus(gtx layout.Context, th *material.Theme) layout.Dimensions {
+	err := d.validationErr
+	if err == nil {
+		if d.live.checking() {
+			return layout.Flex{Axis: layout.Horizontal, Alignment: layout.Middle}.Layout(gtx,
+				layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+					size := gtx.Dp(unit.Dp(14))
+					gtx.Constraints = layout.Exact(image.Pt(size, size))
+					return material.Loader(th).Layout(gtx)
+				}),
+				layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+					return layout.Spacer{Width: unit.Dp(6)}.Layout(gtx)
+				}),
+				layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+					return material.Caption(th, "Validating...").Layout(gtx)
+				}),
+			)
 		}
+		err = d.live.result()
 	}
-	//app.Main()
-	return d.result, d.canceled, nil
+	if err == nil {
+		return layout.Dimensions{}
+	}
+	msg := material.Caption(th, err.Error())
+	msg.Color = errorColor
+	return msg.Layout(gtx)
 }
 
-func (d *inputDialog) layout(gtx layout.Context, th *material.Theme) layout.Dimensions {
-	return layout.UniformInset(unit.Dp(20)).Layout(gtx, func(gtx layout.Context) layout.Dimensions {
-		return layout.Flex{Axis: layout.Vertical, Spacing: layout.SpaceAround}.Layout(gtx,
-			// Label
-			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
-				label := material.H6(th, d.Label)
-				return label.Layout(gtx)
-			}),
-			// Description
-			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
-				if d.Description == "" {
-					return layout.Dimensions{}
-				}
-				desc := material.Body1(th, d.Description)
-				desc.Color = th.Fg
-				return desc.Layout(gtx)
-			}),
-			// Text input
-			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
-				return d.styledEditor(gtx, th)
-			}),
-			// Buttons
-			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
-				return layout.Flex{Axis: layout.Horizontal, Spacing: layout.SpaceEnd}.Layout(gtx,
-					layout.Rigid(func(gtx layout.Context) layout.Dimensions {
-						btn := material.Button(th, &d.cancelButton, "Cancel")
-						return btn.Layout(gtx)
-					}),
-					layout.Rigid(func(gtx layout.Context) layout.Dimensions {
-						return layout.Spacer{Width: unit.Dp(10)}.Layout(gtx)
-					}),
-					layout.Rigid(func(gtx layout.Context) layout.Dimensions {
-						btn := material.Button(th, &d.okButton, "OK")
-						return btn.Layout(gtx)
-					}),
-				)
-			}),
-		)
-	})
+// validate runs the optional Validate function against the current
+// editor content and remembers the outcome for display.
+func (d *inputDialog) validate() {
+	if d.Validate == nil {
+		d.validationErr = nil
+		return
+	}
+	d.validationErr = d.Validate(d.textInput.Text())
 }
 
-func (d *inputDialog) handleOK() {
-	text := d.textInput.Text()
-	if d.Validate != nil {
-		if err := d.Validate(text); err != nil {
-			// TODO: Show validation error in UI
-			return
-		}
+// confirm accepts the current input and reports whether the dialog may be
+// closed. Input that doesn't pass validation keeps the dialog open.
+func (d *inputDialog) confirm() bool {
+	d.validate()
+	if !d.validated {
+		// A zero start time makes the unchanged input validate right away.
+		d.validated = true
+		d.live.schedule(d.textInput.Text(), time.Time{})
+	}
+	d.live.flush()
+	if !d.canConfirm() {
+		return false
 	}
-	d.result = text
-	d.canceled = false
+	d.value = d.textInput.Text()
+	return true
 }
 
-func (d *inputDialog) handleCancel() {
-	d.result = ""
-	d.canceled = true
-}
+func (d *inputDialog) result() Result { return Result{Outcome: d.outcome, Value: d.value} }
@@ -0,0 +1,61 @@
+package dialog
+
+import (
+	"errors"
+	"testing"
+
+	"gioui.org/io/key"
+)
+
+func TestInputValidation(t *testing.T) {
+	errShort := errors.New("enter at least 3 characters")
+	d := NewInputDialog(0, 0, "", "", "", "", func(s string) error {
+		if len(s) < 3 {
+			return errShort
+		}
+		return nil
+	})
+	h := newHarness(t, d)
+
+	h.typeText("ab")
+	if d.canConfirm() {
+		t.Error("invalid input can be confirmed")
+	}
+	if d.validationErr != errShort {
+		t.Errorf("validation error = %v, want %v", d.validationErr, errShort)
+	}
+	if h.press(key.NameReturn) {
+		t.Fatal("Enter closed the dialog with invalid input")
+	}
+
+	h.typeText("c")
+	if !d.canConfirm() || d.validationErr != nil {
+		t.Errorf("valid input can't be confirmed: %v", d.validationErr)
+	}
+	if !h.press(key.NameReturn) {
+		t.Fatal("Enter didn't confirm valid input")
+	}
+	if res := d.result(); res.Outcome != Confirmed || res.Value != "abc" {
+		t.Errorf("got %+v, want the confirmed input", res)
+	}
+}
+
+func TestInputValidatedOnChange(t *testing.T) {
+	d := NewInputDialog(0, 0, "", "", "", "", func(s string) error {
+		if s == "" {
+			return errors.New("required")
+		}
+		return nil
+	})
+	h := newHarness(t, d)
+
+	if d.validationErr != nil {
+		t.Errorf("error %v shown before the input changed", d.validationErr)
+	}
+	h.typeText("a")
+	d.textInput.SetCaret(1, 0)
+	h.typeInto(&d.textInput, "")
+	if d.validationErr == nil || d.canConfirm() {
+		t.Error("no error shown for the cleared input")
+	}
+}
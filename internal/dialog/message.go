@@ -0,0 +1,74 @@
+package dialog
+
+import (
+	"gioui.org/io/event"
+	"gioui.org/layout"
+	"gioui.org/unit"
+	"gioui.org/widget"
+	"gioui.org/widget/material"
+)
+
+// Severity defines the icon and buttons of a message dialog.
+type Severity int
+
+const (
+	// SeverityInfo shows an information with a single OK button.
+	SeverityInfo Severity = iota
+	// SeverityWarning shows a warning with a single OK button.
+	SeverityWarning
+	// SeverityError shows an error with a single OK button.
+	SeverityError
+	// SeverityQuestion asks a question with Yes and No buttons.
+	SeverityQuestion
+)
+
+// messageDialog is the internal implementation of a message dialog: a
+// BaseDialog with a severity icon and a selectable message.
+type messageDialog struct {
+	BaseDialog
+	Severity Severity
+	Message  string
+
+	// UI state
+	text widget.Selectable
+}
+
+// NewMessageDialog initializes a messageDialog from provided parameters.
+func NewMessageDialog(width, height float32, title, label, message string, severity Severity) *messageDialog {
+	if height <= 0 {
+		height = 200
+	}
+	d := &messageDialog{
+		BaseDialog: *NewBaseDialog(width, height, title, label, ""),
+		Severity:   severity,
+		Message:    message,
+	}
+	if severity == SeverityQuestion {
+		d.Buttons = []Button{
+			{Label: "No", Role: RoleNegative, Cancel: true},
+			{Label: "Yes", Role: RoleAffirmative, Default: true},
+		}
+	} else {
+		d.Buttons = []Button{{Label: "OK", Role: RoleAffirmative, Default: true}}
+	}
+	return d
+}
+
+// initialFocus focuses the message, so that it can be copied right away.
+func (d *messageDialog) initialFocus() event.Tag { return &d.text }
+
+func (d *messageDialog) layoutBody(gtx layout.Context, th *material.Theme) layout.Dimensions {
+	return layout.Flex{Axis: layout.Horizontal, Alignment: layout.Middle}.Layout(gtx,
+		layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+			return layoutSeverityIcon(gtx, d.Severity, gtx.Dp(unit.Dp(40)))
+		}),
+		layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+			return layout.Spacer{Width: unit.Dp(16)}.Layout(gtx)
+		}),
+		layout.Flexed(1, func(gtx layout.Context) layout.Dimensions {
+			msg := material.Body1(th, d.Message)
+			msg.State = &d.text
+			return msg.Layout(gtx)
+		}),
+	)
+}
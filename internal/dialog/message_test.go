@@ -0,0 +1,51 @@
+package dialog
+
+import (
+	"slices"
+	"testing"
+
+	"gioui.org/io/key"
+)
+
+func TestMessageDialog(t *testing.T) {
+	tests := []struct {
+		name     string
+		severity Severity
+		key      key.Name
+		want     Outcome
+	}{
+		{"info/return", SeverityInfo, key.NameReturn, Confirmed},
+		{"info/escape", SeverityInfo, key.NameEscape, Canceled},
+		{"warning/return", SeverityWarning, key.NameReturn, Confirmed},
+		{"error/return", SeverityError, key.NameReturn, Confirmed},
+		{"question/yes", SeverityQuestion, key.NameReturn, Confirmed},
+		{"question/no", SeverityQuestion, key.NameEscape, Canceled},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := NewMessageDialog(0, 0, "", "", "Something happened.", tt.severity)
+			h := newHarness(t, d)
+			if !h.press(tt.key) {
+				t.Fatalf("dialog still open after %s", tt.key)
+			}
+			if got := d.result().Outcome; got != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMessageDialogButtons(t *testing.T) {
+	info := NewMessageDialog(0, 0, "", "", "", SeverityInfo)
+	if len(info.Buttons) != 1 || info.Buttons[0].Label != "OK" {
+		t.Errorf("info dialog buttons: got %v, want a single OK", info.Buttons)
+	}
+	question := NewMessageDialog(0, 0, "", "", "", SeverityQuestion)
+	var labels []string
+	for _, b := range question.Buttons {
+		labels = append(labels, b.Label)
+	}
+	if !slices.Equal(labels, []string{"No", "Yes"}) {
+		t.Errorf("question dialog buttons: got %v, want No/Yes", labels)
+	}
+}
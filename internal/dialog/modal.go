@@ -0,0 +1,107 @@
+package dialog
+
+import (
+	"image"
+	"image/color"
+
+	"gioui.org/io/event"
+	"gioui.org/io/pointer"
+	"gioui.org/layout"
+	"gioui.org/op/clip"
+	"gioui.org/op/paint"
+	"gioui.org/unit"
+	"gioui.org/widget/material"
+)
+
+// scrimColor dims the content of the host window behind a modal dialog.
+var scrimColor = color.NRGBA{A: 128}
+
+// Modal draws a dialog on top of a scrim inside the window of the caller,
+// instead of opening a window of its own.
+type Modal struct {
+	dialog     Dialog
+	invalidate func()
+	opened     bool
+	closed     bool
+}
+
+// NewModal wraps one of the dialogs of this package for in-window use.
+// invalidate requests a new frame of the window the modal is drawn in. It
+// is called from other goroutines when the dialog changes without user
+// input, e.g. once an asynchronous validation finished, and may be nil.
+func NewModal(d Dialog, invalidate func()) *Modal {
+	return &Modal{dialog: d, invalidate: invalidate}
+}
+
+// Layout draws the scrim and the dialog centered on top of it, using the
+// full space available in gtx. It reports whether the user closed the
+// dialog during this frame; after that, Layout draws nothing.
+func (m *Modal) Layout(gtx layout.Context, th *material.Theme) (layout.Dimensions, bool) {
+	if m.closed {
+		return layout.Dimensions{}, false
+	}
+	if !m.opened {
+		m.opened = true
+		m.dialog.open(m.invalidate)
+	}
+
+	size := gtx.Constraints.Max
+	defer clip.Rect{Max: size}.Push(gtx.Ops).Pop()
+	paint.Fill(gtx.Ops, scrimColor)
+	// Swallow pointer input, so that widgets below the scrim can't be used
+	// while the dialog is open.
+	for {
+		if _, ok := gtx.Event(pointer.Filter{Target: m, Kinds: pointer.Press | pointer.Release | pointer.Scroll}); !ok {
+			break
+		}
+	}
+	event.Op(gtx.Ops, m)
+
+	closed := false
+	layout.Center.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+		b := m.dialog.base()
+		width, height := b.Width, b.Height
+		cardSize := image.Pt(
+			min(gtx.Dp(unit.Dp(width)), gtx.Constraints.Max.X),
+			min(gtx.Dp(unit.Dp(height)), gtx.Constraints.Max.Y),
+		)
+		gtx.Constraints = layout.Exact(cardSize)
+
+		defer clip.UniformRRect(image.Rectangle{Max: cardSize}, gtx.Dp(unit.Dp(8))).Push(gtx.Ops).Pop()
+		paint.Fill(gtx.Ops, th.Bg)
+
+		return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+			// Title, which is shown as the window title otherwise
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				if b.Title == "" {
+					return layout.Dimensions{}
+				}
+				return layout.Inset{Top: unit.Dp(16), Left: unit.Dp(20), Right: unit.Dp(20)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+					title := material.Subtitle1(th, b.Title)
+					title.MaxLines = 1
+					return title.Layout(gtx)
+				})
+			}),
+			layout.Flexed(1, func(gtx layout.Context) layout.Dimensions {
+				closed = frame(gtx, th, m.dialog)
+				return layout.Dimensions{Size: gtx.Constraints.Max}
+			}),
+		)
+	})
+
+	if closed {
+		m.closed = true
+		m.dialog.close()
+	}
+	return layout.Dimensions{Size: size}, closed
+}
+
+// Closed reports whether the user closed the dialog.
+func (m *Modal) Closed() bool {
+	return m.closed
+}
+
+// Result returns the result of the dialog once it was closed.
+func (m *Modal) Result() Result {
+	return result(m.dialog)
+}
@@ -0,0 +1,103 @@
+package dialog
+
+import (
+	"image"
+	"testing"
+	"time"
+
+	"gioui.org/io/key"
+	"gioui.org/layout"
+	"gioui.org/op"
+	"gioui.org/widget/material"
+)
+
+// newModalHarness drives m like a window that draws it on every frame.
+func newModalHarness(t *testing.T, m *Modal) *harness {
+	t.Helper()
+	h := &harness{t: t, d: m.dialog, modal: m, th: material.NewTheme(), now: time.Now()}
+	t.Cleanup(func() {
+		if !m.Closed() {
+			m.dialog.close()
+		}
+	})
+	h.frame()
+	h.frame()
+	return h
+}
+
+func TestModal(t *testing.T) {
+	tests := []struct {
+		name string
+		key  key.Name
+		want Result
+	}{
+		{"confirm", key.NameReturn, Result{Outcome: Confirmed, Value: "gio"}},
+		{"cancel", key.NameEscape, Result{Outcome: Canceled}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := NewInputDialog(0, 0, "Title", "Label", "", "gio", nil)
+			m := NewModal(d, nil)
+			if m.Closed() {
+				t.Fatal("new modal is closed")
+			}
+			h := newModalHarness(t, m)
+			// The first frame opened the dialog.
+			if len(d.buttons) == 0 {
+				t.Fatal("dialog wasn't opened")
+			}
+			if m.Closed() {
+				t.Fatal("modal closed without input")
+			}
+
+			if !h.press(tt.key) {
+				t.Fatalf("%s didn't close the modal", tt.key)
+			}
+			if !m.Closed() {
+				t.Error("Closed() = false after closing")
+			}
+			if got := m.Result(); got.Outcome != tt.want.Outcome || got.Value != tt.want.Value {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+
+			// A closed modal draws nothing, and reports the close once.
+			gtx := layout.Context{Ops: new(op.Ops), Now: h.now, Constraints: layout.Exact(image.Pt(400, 300))}
+			dims, closed := m.Layout(gtx, h.th)
+			if closed || dims.Size.X != 0 || dims.Size.Y != 0 {
+				t.Errorf("Layout() after close = %v, %t", dims, closed)
+			}
+		})
+	}
+}
+
+func TestModalAsyncValidation(t *testing.T) {
+	validate, calls := asyncValidator()
+	d := NewInputDialog(0, 0, "", "", "", "", nil)
+	d.ValidateDelay = testValidateDelay
+	d.ValidateAsync = validate
+	invalidated := make(chan struct{}, 4)
+	m := NewModal(d, func() { invalidated <- struct{}{} })
+	h := newModalHarness(t, m)
+
+	h.typeText("gio")
+	h.wait()
+	c := nextCall(t, calls)
+	if d.canConfirm() {
+		t.Error("OK enabled while validating")
+	}
+	c.result <- nil
+	// The result of the validation requests a new frame of the window.
+	waitDone(t, h, invalidated)
+	if !d.canConfirm() {
+		t.Error("OK disabled after the validation passed")
+	}
+	if !h.press(key.NameReturn) {
+		t.Fatal("Enter didn't confirm valid input")
+	}
+	if got := m.Result(); got.Outcome != Confirmed || got.Value != "gio" {
+		t.Errorf("got %+v, want the confirmed input", got)
+	}
+	if d.live.checking() || d.live.busy() {
+		t.Error("validation still running after the modal closed")
+	}
+}
@@ -0,0 +1,190 @@
+package dialog
+
+import (
+	"fmt"
+	"slices"
+
+	"gioui.org/layout"
+	"gioui.org/unit"
+	"gioui.org/widget"
+	"gioui.org/widget/material"
+)
+
+// multiSelectDialog is the internal implementation of a checklist dialog.
+type multiSelectDialog struct {
+	BaseDialog
+	Choices           []string
+	DefaultSelections []string
+	// MinSelections and MaxSelections limit the number of checked choices
+	// the dialog can be confirmed with. Zero means no limit.
+	MinSelections int
+	MaxSelections int
+
+	// internal result state
+	selected []string
+
+	// UI state
+	checks     []widget.Bool
+	selectAll  widget.Clickable
+	selectNone widget.Clickable
+	list       widget.List
+}
+
+// NewMultiSelectDialog initializes a multiSelectDialog from provided parameters.
+func NewMultiSelectDialog(width, height float32, title, label, description string, choices, defaultSelections []string, minSelections, maxSelections int) *multiSelectDialog {
+	if height <= 0 {
+		height = 360
+	}
+	d := &multiSelectDialog{
+		BaseDialog:        *NewBaseDialog(width, height, title, label, description),
+		Choices:           choices,
+		DefaultSelections: defaultSelections,
+		MinSelections:     minSelections,
+		MaxSelections:     maxSelections,
+	}
+
+	// Check the default selections
+	d.checks = make([]widget.Bool, len(choices))
+	for i, choice := range choices {
+		d.checks[i].Value = slices.Contains(defaultSelections, choice)
+	}
+
+	// Initialize scrollable list
+	d.list.Axis = layout.Vertical
+
+	return d
+}
+
+// update processes the select all/none controls.
+func (d *multiSelectDialog) update(gtx layout.Context) bool {
+	if d.selectAll.Clicked(gtx) {
+		d.countdown.pause()
+		d.checkAll(true)
+	}
+	if d.selectNone.Clicked(gtx) {
+		d.countdown.pause()
+		d.checkAll(false)
+	}
+	return false
+}
+
+func (d *multiSelectDialog) checkAll(value bool) {
+	for i := range d.checks {
+		d.checks[i].Value = value
+	}
+}
+
+// count returns the number of checked choices.
+func (d *multiSelectDialog) count() int {
+	n := 0
+	for _, c := range d.checks {
+		if c.Value {
+			n++
+		}
+	}
+	return n
+}
+
+// withinLimits reports whether n checked choices satisfy the limits.
+func (d *multiSelectDialog) withinLimits(n int) bool {
+	return n >= d.MinSelections && (d.MaxSelections <= 0 || n <= d.MaxSelections)
+}
+
+func (d *multiSelectDialog) layoutBody(gtx layout.Context, th *material.Theme) layout.Dimensions {
+	materialList := material.List(th, &d.list)
+	materialList.AnchorStrategy = material.Occupy
+	return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+		// Select all/none controls
+		layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+			return layout.Flex{Axis: layout.Horizontal}.Layout(gtx,
+				layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+					// Selecting all choices would exceed the maximum
+					if !d.withinLimits(len(d.Choices)) {
+						gtx = gtx.Disabled()
+					}
+					return flatButton(gtx, th, &d.selectAll, "Select all")
+				}),
+				layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+					return flatButton(gtx, th, &d.selectNone, "Select none")
+				}),
+			)
+		}),
+		// Choices with scrollable list
+		layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+			// Set height to show approximately 5 checkboxes
+			maxHeight := unit.Dp(160)
+			gtx.Constraints.Max.Y = gtx.Dp(maxHeight)
+
+			full := d.MaxSelections > 0 && d.count() >= d.MaxSelections
+			return materialList.Layout(gtx, len(d.Choices), func(gtx layout.Context, i int) layout.Dimensions {
+				if d.checks[i].Update(gtx) {
+					d.countdown.pause()
+				}
+				// Unchecked choices can't be checked once the maximum is reached
+				if full && !d.checks[i].Value {
+					gtx = gtx.Disabled()
+				}
+				return material.CheckBox(th, &d.checks[i], d.Choices[i]).Layout(gtx)
+			})
+		}),
+		// Number of checked choices and the limits
+		layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+			n := d.count()
+			status := material.Caption(th, d.status(n))
+			if !d.withinLimits(n) {
+				status.Color = errorColor
+			}
+			return status.Layout(gtx)
+		}),
+	)
+}
+
+// status describes the number of checked choices n and the limits.
+func (d *multiSelectDialog) status(n int) string {
+	lo, hi := d.MinSelections, d.MaxSelections
+	switch {
+	case lo > 0 && lo == hi:
+		return fmt.Sprintf("%d of %d selected", n, hi)
+	case lo > 0 && hi > 0:
+		return fmt.Sprintf("%d selected (%d to %d)", n, lo, hi)
+	case lo > 0:
+		return fmt.Sprintf("%d selected (at least %d)", n, lo)
+	case hi > 0:
+		return fmt.Sprintf("%d selected (at most %d)", n, hi)
+	default:
+		return fmt.Sprintf("%d selected", n)
+	}
+}
+
+// flatButton lays out a button without background, for secondary actions.
+func flatButton(gtx layout.Context, th *material.Theme, c *widget.Clickable, text string) layout.Dimensions {
+	btn := material.Button(th, c, text)
+	btn.Background = th.Bg
+	btn.Color = th.Palette.ContrastBg
+	btn.Inset = layout.UniformInset(unit.Dp(6))
+	return btn.Layout(gtx)
+}
+
+// canConfirm reports whether the number of checked choices is within the
+// limits.
+func (d *multiSelectDialog) canConfirm() bool {
+	return d.withinLimits(d.count())
+}
+
+// confirm accepts the checked choices, in the order of Choices.
+func (d *multiSelectDialog) confirm() bool {
+	if !d.canConfirm() {
+		return false
+	}
+	d.selected = []string{}
+	for i, c := range d.checks {
+		if c.Value {
+			d.selected = append(d.selected, d.Choices[i])
+		}
+	}
+	return true
+}
+
+func (d *multiSelectDialog) result() Result {
+	return Result{Outcome: d.outcome, Values: d.selected}
+}
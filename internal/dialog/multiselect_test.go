@@ -0,0 +1,84 @@
+package dialog
+
+import (
+	"reflect"
+	"testing"
+
+	"gioui.org/io/key"
+)
+
+func TestMultiSelect(t *testing.T) {
+	choices := []string{"a", "b", "c", "d"}
+
+	tests := []struct {
+		name     string
+		defaults []string
+		min, max int
+		check    func(d *multiSelectDialog)
+		want     []string // nil if Enter keeps the dialog open
+	}{
+		{name: "defaults", defaults: []string{"c", "a"}, want: []string{"a", "c"}},
+		{name: "none", want: []string{}},
+		{name: "below min", defaults: []string{"a"}, min: 2},
+		{name: "above max", defaults: []string{"a", "b", "c"}, max: 2},
+		{name: "within limits", defaults: []string{"a", "b"}, min: 1, max: 2, want: []string{"a", "b"}},
+		{
+			name:  "select all",
+			check: func(d *multiSelectDialog) { d.checkAll(true) },
+			want:  choices,
+		},
+		{
+			name:     "select none",
+			defaults: []string{"a", "b"},
+			check:    func(d *multiSelectDialog) { d.checkAll(false) },
+			want:     []string{},
+		},
+		{
+			name:  "select all above max",
+			max:   3,
+			check: func(d *multiSelectDialog) { d.checkAll(true) },
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := NewMultiSelectDialog(0, 0, "", "", "", choices, tt.defaults, tt.min, tt.max)
+			h := newHarness(t, d)
+			if tt.check != nil {
+				tt.check(d)
+			}
+
+			closed := h.press(key.NameReturn)
+			if tt.want == nil {
+				if closed {
+					t.Fatalf("Enter confirmed %v", d.result().Values)
+				}
+				return
+			}
+			if !closed {
+				t.Fatal("Enter didn't confirm the dialog")
+			}
+			if want := (Result{Outcome: Confirmed, Values: tt.want}); !reflect.DeepEqual(d.result(), want) {
+				t.Errorf("got %+v, want %+v", d.result(), want)
+			}
+		})
+	}
+}
+
+func TestMultiSelectStatus(t *testing.T) {
+	tests := []struct {
+		min, max, n int
+		want        string
+	}{
+		{0, 0, 2, "2 selected"},
+		{1, 0, 0, "0 selected (at least 1)"},
+		{0, 3, 1, "1 selected (at most 3)"},
+		{1, 3, 2, "2 selected (1 to 3)"},
+		{2, 2, 1, "1 of 2 selected"},
+	}
+	for _, tt := range tests {
+		d := NewMultiSelectDialog(0, 0, "", "", "", nil, nil, tt.min, tt.max)
+		if got := d.status(tt.n); got != tt.want {
+			t.Errorf("status(%d) with limits %d to %d: got %q, want %q", tt.n, tt.min, tt.max, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package dialog
+
+import (
+	"io/fs"
+	"slices"
+
+	"gioui.org/layout"
+	"gioui.org/widget/material"
+)
+
+// openFileDialog is the internal implementation of a file open dialog. It
+// browses an fs.FS, so that it works on the real filesystem through
+// os.DirFS as well as on fstest.MapFS in tests.
+type openFileDialog struct {
+	BaseDialog
+	// Multiple allows selecting several files of the same directory.
+	Multiple bool
+
+	// Browser lists the directories and files, and holds the file
+	// system, the initial directory and the filters.
+	Browser fileBrowser
+
+	// internal result state
+	paths []string
+
+	// picked holds the names of the selected files in the current
+	// directory; the selection is dropped when another one is opened.
+	picked map[string]bool
+}
+
+// NewOpenFileDialog initializes an openFileDialog from provided parameters.
+// dir is the directory shown first, as a path of fsys.
+func NewOpenFileDialog(width, height float32, title, label, description string, fsys fs.FS, dir string, filters []FileFilter, multiple bool) *openFileDialog {
+	if width <= 0 {
+		width = 560
+	}
+	if height <= 0 {
+		height = 480
+	}
+	d := &openFileDialog{
+		BaseDialog: *NewBaseDialog(width, height, title, label, description),
+		Multiple:   multiple,
+		picked:     map[string]bool{},
+	}
+	d.Buttons = []Button{
+		{Label: "Cancel", Role: RoleNegative, Cancel: true},
+		{Label: "Open", Role: RoleAffirmative, Default: true},
+	}
+	d.Browser.FS, d.Browser.Dir, d.Browser.Filters = fsys, dir, filters
+	return d
+}
+
+// open reads the initial directory, falling back to the root if it can't
+// be read.
+func (d *openFileDialog) open(invalidate func()) {
+	d.BaseDialog.open(invalidate)
+	d.Browser.load(d.Browser.Dir)
+	if err := d.Browser.err; err != nil {
+		d.Browser.load(".")
+		d.Browser.err = err
+	}
+}
+
+// update opens directories on click. Files are selected on click, or
+// toggled if Multiple is set; a double click or Enter opens them.
+func (d *openFileDialog) update(gtx layout.Context) bool {
+	i, activate, changed := d.Browser.update(gtx)
+	if changed {
+		d.countdown.pause()
+		clear(d.picked)
+	}
+	if i < 0 {
+		return false
+	}
+	d.countdown.pause()
+	e := d.Browser.entries[i]
+	switch {
+	case e.dir:
+		d.Browser.load(d.Browser.path(e.name))
+		clear(d.picked)
+	case d.Multiple:
+		if activate {
+			// Enter opens the selection, and the focused file with it
+			d.picked[e.name] = true
+			return true
+		}
+		d.picked[e.name] = !d.picked[e.name]
+	default:
+		clear(d.picked)
+		d.picked[e.name] = true
+		return activate
+	}
+	return false
+}
+
+func (d *openFileDialog) layoutBody(gtx layout.Context, th *material.Theme) layout.Dimensions {
+	return d.Browser.layout(gtx, th, d.isPicked)
+}
+
+// canConfirm reports whether a listed file is selected.
+func (d *openFileDialog) canConfirm() bool {
+	return slices.ContainsFunc(d.Browser.entries, d.isPicked)
+}
+
+// isPicked reports whether e is a selected file.
+func (d *openFileDialog) isPicked(e fileEntry) bool {
+	return !e.dir && d.picked[e.name]
+}
+
+// confirm accepts the selected files, in the order they are listed.
+func (d *openFileDialog) confirm() bool {
+	if !d.canConfirm() {
+		return false
+	}
+	d.paths = nil
+	for _, e := range d.Browser.entries {
+		if d.isPicked(e) {
+			d.paths = append(d.paths, d.Browser.osPath(d.Browser.path(e.name)))
+		}
+	}
+	return true
+}
+
+func (d *openFileDialog) result() Result {
+	r := Result{Outcome: d.outcome, Values: d.paths}
+	if len(d.paths) > 0 {
+		r.Value = d.paths[0]
+	}
+	return r
+}
@@ -0,0 +1,166 @@
+package dialog
+
+import (
+	"errors"
+	"io/fs"
+	"reflect"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"gioui.org/io/key"
+)
+
+func testFS() fstest.MapFS {
+	date := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	file := func(size int, age time.Duration) *fstest.MapFile {
+		return &fstest.MapFile{Data: make([]byte, size), ModTime: date.Add(-age)}
+	}
+	return fstest.MapFS{
+		"README.md":      file(300, 0),
+		"go.mod":         file(40, time.Hour),
+		"logo.PNG":       file(5000, 2*time.Hour),
+		".gitignore":     file(10, 3*time.Hour),
+		"src/main.go":    file(100, 0),
+		"src/util.go":    file(200, 0),
+		"src/doc.txt":    file(50, 0),
+		"docs/guide.md":  file(10, 0),
+		".git/config":    file(10, 0),
+		"docs/img/a.png": file(10, 0),
+	}
+}
+
+// names returns the names of the listed entries, with a slash after
+// directories.
+func names(b *fileBrowser) []string {
+	var s []string
+	for _, e := range b.entries {
+		if e.dir {
+			s = append(s, e.name+"/")
+		} else {
+			s = append(s, e.name)
+		}
+	}
+	return s
+}
+
+func TestFileBrowser(t *testing.T) {
+	b := &fileBrowser{FS: testFS()}
+	b.load(".")
+	check := func(name string, want ...string) {
+		t.Helper()
+		if got := names(b); !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: got %q, want %q", name, got, want)
+		}
+	}
+	check("by name", "docs/", "src/", "go.mod", "logo.PNG", "README.md")
+
+	b.setSort(sortBySize)
+	check("by size", "docs/", "src/", "go.mod", "README.md", "logo.PNG")
+	b.setSort(sortBySize)
+	check("by size descending", "src/", "docs/", "logo.PNG", "README.md", "go.mod")
+	b.setSort(sortByDate)
+	check("by date", "docs/", "src/", "logo.PNG", "go.mod", "README.md")
+
+	b.setSort(sortByName)
+	b.ShowHidden = true
+	b.refresh()
+	check("hidden", ".git/", "docs/", "src/", ".gitignore", "go.mod", "logo.PNG", "README.md")
+
+	b.ShowHidden = false
+	b.Filters = []FileFilter{{Name: "Images", Patterns: []string{"*.png", "*.jpg"}}, {Patterns: []string{"*.md"}}}
+	b.refresh()
+	check("images", "docs/", "src/", "logo.PNG")
+	b.filter = 1
+	b.refresh()
+	check("markdown", "docs/", "src/", "README.md")
+	if got := b.Filters[1].label(); got != "*.md" {
+		t.Errorf("filter label: got %q, want *.md", got)
+	}
+
+	b.DirsOnly = true
+	b.refresh()
+	check("directories", "docs/", "src/")
+
+	b.load("docs/img")
+	if got := b.segments(); !reflect.DeepEqual(got, []string{"docs", "img"}) {
+		t.Errorf("segments: got %q", got)
+	}
+	b.load("missing")
+	if b.Dir != "docs/img" || b.err == nil {
+		t.Errorf("loading a missing directory: got %q, %v", b.Dir, b.err)
+	}
+}
+
+// click clicks the listed entry name through the keyboard.
+func (h *harness) click(b *fileBrowser, name string) {
+	h.t.Helper()
+	for i, e := range b.entries {
+		if e.name == name {
+			h.router.Source().Execute(key.FocusCmd{Tag: &b.rows[i]})
+			h.frame()
+			h.press(key.NameSpace)
+			return
+		}
+	}
+	h.t.Fatalf("%s isn't listed", name)
+}
+
+func TestOpenFile(t *testing.T) {
+	d := NewOpenFileDialog(0, 0, "", "", "", testFS(), ".", nil, false)
+	h := newHarness(t, d)
+
+	if h.press(key.NameReturn) {
+		t.Fatal("Enter confirmed the dialog without a selected file")
+	}
+	h.click(&d.Browser, "src")
+	if d.Browser.Dir != "src" {
+		t.Fatalf("clicking a directory: got %q, want src", d.Browser.Dir)
+	}
+	h.click(&d.Browser, "main.go")
+	h.click(&d.Browser, "util.go")
+	if !h.press(key.NameReturn) {
+		t.Fatal("Enter didn't confirm the selected file")
+	}
+	want := Result{Outcome: Confirmed, Value: "src/util.go", Values: []string{"src/util.go"}}
+	if got := d.result(); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
+
+func TestOpenFileMultiple(t *testing.T) {
+	d := NewOpenFileDialog(0, 0, "", "", "", testFS(), "src", []FileFilter{{Patterns: []string{"*.go"}}}, true)
+	h := newHarness(t, d)
+
+	h.click(&d.Browser, "util.go")
+	h.click(&d.Browser, "main.go")
+	if !h.press(key.NameReturn) {
+		t.Fatal("Enter didn't confirm the selected files")
+	}
+	want := Result{Outcome: Confirmed, Value: "src/main.go", Values: []string{"src/main.go", "src/util.go"}}
+	if got := d.result(); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
+
+func TestOpenFileMissingDir(t *testing.T) {
+	d := NewOpenFileDialog(0, 0, "", "", "", testFS(), "missing", nil, false)
+	newHarness(t, d)
+	if d.Browser.Dir != "." || !errors.Is(d.Browser.err, fs.ErrNotExist) {
+		t.Errorf("got %q, %v; want the root and ErrNotExist", d.Browser.Dir, d.Browser.err)
+	}
+}
+
+func TestFormatSize(t *testing.T) {
+	for n, want := range map[int64]string{
+		0:       "0 B",
+		1023:    "1023 B",
+		1536:    "1.5 KB",
+		5 << 20: "5.0 MB",
+		3 << 40: "3.0 TB",
+	} {
+		if got := formatSize(n); got != want {
+			t.Errorf("formatSize(%d): got %q, want %q", n, got, want)
+		}
+	}
+}
@@ -0,0 +1,223 @@
+package dialog
+
+import (
+	"bytes"
+	"unicode"
+	"unicode/utf8"
+
+	"gioui.org/io/key"
+	"gioui.org/layout"
+	"gioui.org/unit"
+	"gioui.org/widget"
+	"gioui.org/widget/material"
+)
+
+// passwordMask replaces the characters of a hidden password.
+const passwordMask = '•'
+
+// passwordDialog is the internal implementation of a masked secret input
+// dialog. The secret is never converted to a string, and the editors are
+// reset when the dialog closes.
+type passwordDialog struct {
+	inputDialog
+	// Confirmation shows a second field, and the dialog can only be
+	// confirmed if both fields match.
+	Confirmation bool
+
+	// internal result state, handed over to the caller by result
+	secret []byte
+
+	// matches reports whether both fields have the same content.
+	matches bool
+	// shift and capsLock track whether Caps Lock seems to be on: Gio
+	// doesn't report its state, but a letter typed in upper case without
+	// Shift, or vice versa, hints at it.
+	shift    bool
+	capsLock bool
+
+	// UI state
+	confirmInput widget.Editor
+	revealButton widget.Clickable
+	revealed     bool
+}
+
+// NewPasswordDialog initializes a passwordDialog from provided parameters.
+func NewPasswordDialog(width, height float32, title, label, description string, confirmation bool) *passwordDialog {
+	if height <= 0 {
+		height = 200
+		if confirmation {
+			height = 260
+		}
+	}
+	d := &passwordDialog{
+		inputDialog:  inputDialog{BaseDialog: *NewBaseDialog(width, height, title, label, description)},
+		Confirmation: confirmation,
+		matches:      true,
+	}
+	for _, e := range []*widget.Editor{&d.textInput, &d.confirmInput} {
+		e.SingleLine = true
+		e.Submit = true
+	}
+	d.updateMask()
+	return d
+}
+
+// close drops the content of the editors, including their undo history.
+func (d *passwordDialog) close() {
+	d.inputDialog.close()
+	d.textInput = widget.Editor{}
+	d.confirmInput = widget.Editor{}
+}
+
+// update processes the events of both fields and the show/hide toggle.
+// Enter in the first field moves on to the confirmation field, if any.
+func (d *passwordDialog) update(gtx layout.Context) bool {
+	for {
+		ev, ok := gtx.Event(
+			key.Filter{Focus: &d.textInput, Name: key.NameShift},
+			key.Filter{Focus: &d.confirmInput, Name: key.NameShift},
+		)
+		if !ok {
+			break
+		}
+		if e, ok := ev.(key.Event); ok {
+			d.shift = e.State == key.Press
+		}
+	}
+
+	submit := false
+	for _, e := range []*widget.Editor{&d.textInput, &d.confirmInput} {
+		for {
+			ev, ok := e.Update(gtx)
+			if !ok {
+				break
+			}
+			switch ev.(type) {
+			case widget.ChangeEvent:
+				d.countdown.pause()
+				d.checkCapsLock(e)
+				d.compare()
+			case widget.SubmitEvent:
+				if e == &d.textInput && d.Confirmation {
+					gtx.Execute(key.FocusCmd{Tag: &d.confirmInput})
+				} else {
+					submit = true
+				}
+			}
+		}
+	}
+
+	if d.revealButton.Clicked(gtx) {
+		d.countdown.pause()
+		d.revealed = !d.revealed
+		d.updateMask()
+	}
+	return submit
+}
+
+func (d *passwordDialog) updateMask() {
+	var mask rune
+	if !d.revealed {
+		mask = passwordMask
+	}
+	d.textInput.Mask = mask
+	d.confirmInput.Mask = mask
+}
+
+// checkCapsLock checks the case of the letter before the caret of e.
+func (d *passwordDialog) checkCapsLock(e *widget.Editor) {
+	start, end := e.Selection()
+	if start != end || start == 0 {
+		return
+	}
+	content := editorBytes(e)
+	defer clear(content)
+	var r rune
+	for i, n := 0, 0; n < start && i < len(content); n++ {
+		var size int
+		r, size = utf8.DecodeRune(content[i:])
+		i += size
+	}
+	if unicode.IsUpper(r) || unicode.IsLower(r) {
+		d.capsLock = unicode.IsUpper(r) != d.shift
+	}
+}
+
+// compare checks whether both fields match.
+func (d *passwordDialog) compare() {
+	if !d.Confirmation {
+		return
+	}
+	a, b := editorBytes(&d.textInput), editorBytes(&d.confirmInput)
+	d.matches = bytes.Equal(a, b)
+	clear(a)
+	clear(b)
+}
+
+func (d *passwordDialog) layoutBody(gtx layout.Context, th *material.Theme) layout.Dimensions {
+	return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+		// Password field with show/hide toggle
+		layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+			return layout.Flex{Axis: layout.Horizontal, Alignment: layout.Middle}.Layout(gtx,
+				layout.Flexed(1, func(gtx layout.Context) layout.Dimensions {
+					return styledEditor(gtx, th, &d.textInput, "")
+				}),
+				layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+					text := "Show"
+					if d.revealed {
+						text = "Hide"
+					}
+					return flatButton(gtx, th, &d.revealButton, text)
+				}),
+			)
+		}),
+		// Confirmation field if enabled
+		layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+			if !d.Confirmation {
+				return layout.Dimensions{}
+			}
+			return layout.Inset{Top: unit.Dp(6)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+				return styledEditor(gtx, th, &d.confirmInput, "Repeat password")
+			})
+		}),
+		// Mismatch error and Caps Lock hint
+		layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+			if d.matches || d.confirmInput.Len() == 0 {
+				return layout.Dimensions{}
+			}
+			msg := material.Caption(th, "Passwords don't match")
+			msg.Color = errorColor
+			return msg.Layout(gtx)
+		}),
+		layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+			if !d.capsLock {
+				return layout.Dimensions{}
+			}
+			return material.Caption(th, "Caps Lock may be on").Layout(gtx)
+		}),
+	)
+}
+
+// canConfirm reports whether the confirmation field, if any, matches.
+func (d *passwordDialog) canConfirm() bool {
+	return d.matches
+}
+
+// confirm copies the secret out of the editor.
+func (d *passwordDialog) confirm() bool {
+	d.compare()
+	if !d.canConfirm() {
+		return false
+	}
+	clear(d.secret)
+	d.secret = editorBytes(&d.textInput)
+	return true
+}
+
+// result hands the secret over to the caller, who is responsible for
+// zeroing it. The dialog doesn't keep a reference to it.
+func (d *passwordDialog) result() Result {
+	r := Result{Outcome: d.outcome, Secret: d.secret}
+	d.secret = nil
+	return r
+}
@@ -0,0 +1,94 @@
+package dialog
+
+import (
+	"testing"
+
+	"gioui.org/io/key"
+)
+
+func TestPassword(t *testing.T) {
+	d := NewPasswordDialog(0, 0, "", "", "", false)
+	h := newHarness(t, d)
+
+	h.typeText("s3cret")
+	if d.textInput.Mask != passwordMask {
+		t.Errorf("password not masked")
+	}
+	if !h.press(key.NameReturn) {
+		t.Fatal("Enter didn't confirm the dialog")
+	}
+	res := d.result()
+	if res.Outcome != Confirmed || string(res.Secret) != "s3cret" {
+		t.Errorf("got %+v, want the confirmed secret", res)
+	}
+
+	d.close()
+	if d.secret != nil || d.textInput.Len() != 0 {
+		t.Error("secret kept in dialog state after close")
+	}
+}
+
+func TestPasswordConfirmation(t *testing.T) {
+	d := NewPasswordDialog(0, 0, "", "", "", true)
+	h := newHarness(t, d)
+
+	h.typeText("s3cret")
+	// Enter moves on to the confirmation field
+	if h.press(key.NameReturn) {
+		t.Fatal("Enter confirmed the dialog without confirmation")
+	}
+	h.typeText("s3cre")
+	if h.press(key.NameReturn) {
+		t.Fatal("Enter confirmed a mismatching password")
+	}
+	h.typeText("t")
+	if !h.press(key.NameReturn) {
+		t.Fatal("Enter didn't confirm a matching password")
+	}
+	if res := d.result(); string(res.Secret) != "s3cret" {
+		t.Errorf("got secret %q, want %q", res.Secret, "s3cret")
+	}
+	if d.result().Secret != nil {
+		t.Error("secret handed out twice")
+	}
+}
+
+func TestPasswordReveal(t *testing.T) {
+	d := NewPasswordDialog(0, 0, "", "", "", true)
+	newHarness(t, d)
+
+	d.revealed = true
+	d.updateMask()
+	if d.textInput.Mask != 0 || d.confirmInput.Mask != 0 {
+		t.Error("password still masked after Show")
+	}
+	d.revealed = false
+	d.updateMask()
+	if d.textInput.Mask != passwordMask || d.confirmInput.Mask != passwordMask {
+		t.Error("password not masked after Hide")
+	}
+}
+
+func TestPasswordCapsLock(t *testing.T) {
+	d := NewPasswordDialog(0, 0, "", "", "", false)
+	h := newHarness(t, d)
+
+	h.typeInto(&d.textInput, "ab")
+	if d.capsLock {
+		t.Error("Caps Lock hint for lower case letters")
+	}
+	h.typeInto(&d.textInput, "C")
+	if !d.capsLock {
+		t.Error("no Caps Lock hint for an upper case letter typed without Shift")
+	}
+	h.typeInto(&d.textInput, "1")
+	if !d.capsLock {
+		t.Error("digits changed the Caps Lock hint")
+	}
+
+	h.router.Queue(key.Event{Name: key.NameShift, State: key.Press})
+	h.typeInto(&d.textInput, "D")
+	if d.capsLock {
+		t.Error("Caps Lock hint for an upper case letter typed with Shift")
+	}
+}
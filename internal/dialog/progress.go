@@ -0,0 +1,209 @@
+package dialog
+
+import (
+	"fmt"
+	"image"
+	"math"
+	"sync"
+	"time"
+
+	"gioui.org/layout"
+	"gioui.org/op"
+	"gioui.org/op/clip"
+	"gioui.org/op/paint"
+	"gioui.org/unit"
+	"gioui.org/widget/material"
+)
+
+// pulsePeriod is the time the indicator of a pulsating progress bar takes
+// to move from one end to the other and back.
+const pulsePeriod = 2 * time.Second
+
+// progressDialog is the internal implementation of a progress dialog: a
+// progress bar with a status text, which is updated from other goroutines
+// while the work is done. The OK button is enabled once the work is
+// complete.
+type progressDialog struct {
+	BaseDialog
+	// AutoClose closes the dialog as soon as the work is complete.
+	AutoClose bool
+
+	mu sync.Mutex
+	// fraction is the progress between 0 and 1.
+	fraction  float32
+	text      string
+	pulsating bool
+	complete  bool
+	closing   bool
+	// invalidate requests a new frame after an update. It is called from
+	// the goroutines doing the work.
+	invalidate func()
+}
+
+// NewProgressDialog initializes a progressDialog from provided parameters.
+// text is the initial status text below the label.
+func NewProgressDialog(width, height float32, title, label, description, text string) *progressDialog {
+	if height <= 0 {
+		height = 200
+	}
+	return &progressDialog{
+		BaseDialog: *NewBaseDialog(width, height, title, label, description),
+		text:       text,
+	}
+}
+
+func (d *progressDialog) open(invalidate func()) {
+	d.BaseDialog.open(invalidate)
+	d.mu.Lock()
+	d.invalidate = invalidate
+	d.mu.Unlock()
+}
+
+func (d *progressDialog) close() {
+	d.mu.Lock()
+	d.invalidate = nil
+	d.mu.Unlock()
+}
+
+// changed requests a new frame after an update of the progress. It must be
+// called without holding d.mu.
+func (d *progressDialog) changed() {
+	d.mu.Lock()
+	invalidate := d.invalidate
+	d.mu.Unlock()
+	if invalidate != nil {
+		invalidate()
+	}
+}
+
+// SetPercent sets the progress, between 0 and 100, and stops pulsating.
+// Reaching 100 completes the work.
+func (d *progressDialog) SetPercent(percent float64) {
+	d.mu.Lock()
+	d.fraction = float32(min(max(percent, 0), 100) / 100)
+	d.pulsating = false
+	if d.fraction >= 1 {
+		d.complete = true
+	}
+	d.mu.Unlock()
+	d.changed()
+}
+
+// SetText sets the status text.
+func (d *progressDialog) SetText(text string) {
+	d.mu.Lock()
+	d.text = text
+	d.mu.Unlock()
+	d.changed()
+}
+
+// SetPulsating switches between a bar showing the progress and a pulsating
+// one, for work whose duration is unknown.
+func (d *progressDialog) SetPulsating(pulsating bool) {
+	d.mu.Lock()
+	d.pulsating = pulsating
+	d.mu.Unlock()
+	d.changed()
+}
+
+// Complete marks the work as done: the bar is filled, and the dialog can be
+// confirmed, or closes if AutoClose is set.
+func (d *progressDialog) Complete() {
+	d.mu.Lock()
+	d.fraction, d.pulsating, d.complete = 1, false, true
+	d.mu.Unlock()
+	d.changed()
+}
+
+// Close completes the work and closes the dialog.
+func (d *progressDialog) Close() {
+	d.mu.Lock()
+	d.fraction, d.pulsating, d.complete, d.closing = 1, false, true, true
+	d.mu.Unlock()
+	d.changed()
+}
+
+// update confirms the dialog once it is closed by the caller, or by
+// AutoClose. It doesn't press a button, since custom buttons may not
+// have a default one.
+func (d *progressDialog) update(gtx layout.Context) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.closing || d.complete && d.AutoClose {
+		d.outcome, d.finished = Confirmed, true
+	}
+	return false
+}
+
+func (d *progressDialog) layoutBody(gtx layout.Context, th *material.Theme) layout.Dimensions {
+	d.mu.Lock()
+	fraction, text, pulsating := d.fraction, d.text, d.pulsating
+	d.mu.Unlock()
+
+	return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+		// Status text
+		layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+			if text == "" {
+				return layout.Dimensions{}
+			}
+			return layout.Inset{Bottom: unit.Dp(8)}.Layout(gtx, material.Body2(th, text).Layout)
+		}),
+		// Progress bar and percentage
+		layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+			return layout.Flex{Axis: layout.Horizontal, Alignment: layout.Middle}.Layout(gtx,
+				layout.Flexed(1, func(gtx layout.Context) layout.Dimensions {
+					if pulsating {
+						return layoutPulse(gtx, th)
+					}
+					bar := material.ProgressBar(th, fraction)
+					bar.Height, bar.Radius = unit.Dp(6), unit.Dp(3)
+					return bar.Layout(gtx)
+				}),
+				layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+					if pulsating {
+						return layout.Dimensions{}
+					}
+					return layout.Inset{Left: unit.Dp(10)}.Layout(gtx,
+						material.Body2(th, fmt.Sprintf("%d%%", int(fraction*100))).Layout)
+				}),
+			)
+		}),
+	)
+}
+
+// layoutPulse draws a progress bar whose indicator moves back and forth,
+// and requests the frames for the animation.
+func layoutPulse(gtx layout.Context, th *material.Theme) layout.Dimensions {
+	bar := material.ProgressBar(th, 0)
+	size := image.Pt(gtx.Constraints.Max.X, gtx.Dp(unit.Dp(6)))
+	radius := gtx.Dp(unit.Dp(3))
+
+	track := clip.UniformRRect(image.Rectangle{Max: size}, radius).Push(gtx.Ops)
+	paint.ColorOp{Color: bar.TrackColor}.Add(gtx.Ops)
+	paint.PaintOp{}.Add(gtx.Ops)
+
+	// The indicator covers a quarter of the track, and moves smoothly
+	// with a cosine from one end to the other.
+	width := size.X / 4
+	phase := float64(gtx.Now.UnixNano()%int64(pulsePeriod)) / float64(pulsePeriod)
+	x := int(float64(size.X-width) * (1 - math.Cos(2*math.Pi*phase)) / 2)
+	offset := op.Offset(image.Pt(x, 0)).Push(gtx.Ops)
+	indicator := clip.UniformRRect(image.Rectangle{Max: image.Pt(width, size.Y)}, radius).Push(gtx.Ops)
+	paint.ColorOp{Color: bar.Color}.Add(gtx.Ops)
+	paint.PaintOp{}.Add(gtx.Ops)
+	indicator.Pop()
+	offset.Pop()
+	track.Pop()
+
+	gtx.Execute(op.InvalidateCmd{})
+	return layout.Dimensions{Size: size}
+}
+
+// canConfirm reports whether the work is complete.
+func (d *progressDialog) canConfirm() bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.complete
+}
+
+func (d *progressDialog) confirm() bool { return d.canConfirm() }
@@ -0,0 +1,97 @@
+package dialog
+
+import (
+	"testing"
+
+	"gioui.org/io/key"
+)
+
+func TestProgress(t *testing.T) {
+	d := NewProgressDialog(0, 0, "", "", "", "Starting")
+	h := newHarness(t, d)
+
+	d.SetPercent(42)
+	d.SetText("Copying")
+	if h.press(key.NameReturn) {
+		t.Fatal("Enter closed the dialog before the work was complete")
+	}
+	d.SetPulsating(true)
+	if h.frame() {
+		t.Fatal("the dialog closed while pulsating")
+	}
+	d.SetPercent(100)
+	if h.frame() {
+		t.Fatal("the dialog closed without AutoClose")
+	}
+	if !h.press(key.NameReturn) {
+		t.Fatal("Enter didn't confirm the completed dialog")
+	}
+	if got := result(d); got.Outcome != Confirmed {
+		t.Errorf("got %s, want confirmed", got.Outcome)
+	}
+}
+
+func TestProgressAutoClose(t *testing.T) {
+	d := NewProgressDialog(0, 0, "", "", "", "")
+	d.AutoClose = true
+	h := newHarness(t, d)
+
+	d.SetPulsating(true)
+	if h.frame() {
+		t.Fatal("the dialog closed before the work was complete")
+	}
+	d.Complete()
+	if !h.frame() {
+		t.Fatal("the completed dialog didn't close")
+	}
+	if got := result(d); got.Outcome != Confirmed {
+		t.Errorf("got %s, want confirmed", got.Outcome)
+	}
+}
+
+func TestProgressClose(t *testing.T) {
+	d := NewProgressDialog(0, 0, "", "", "", "")
+	h := newHarness(t, d)
+
+	d.Close()
+	if !h.frame() {
+		t.Fatal("Close didn't close the dialog")
+	}
+	if got := result(d); got.Outcome != Confirmed {
+		t.Errorf("got %s, want confirmed", got.Outcome)
+	}
+}
+
+func TestProgressCloseCustomButtons(t *testing.T) {
+	for _, autoClose := range []bool{false, true} {
+		d := NewProgressDialog(0, 0, "", "", "", "")
+		d.Buttons = []Button{{Label: "Stop", Cancel: true}, {Label: "Details"}}
+		d.AutoClose = autoClose
+		h := newHarness(t, d)
+
+		if autoClose {
+			d.Complete()
+		} else {
+			d.Close()
+		}
+		if !h.frame() {
+			t.Fatalf("AutoClose %t: the dialog without a default button didn't close", autoClose)
+		}
+		if got := result(d); got.Outcome != Confirmed || got.Button != (Button{}) {
+			t.Errorf("AutoClose %t: got %+v, want confirmed without a button", autoClose, got)
+		}
+	}
+}
+
+func TestProgressCancel(t *testing.T) {
+	d := NewProgressDialog(0, 0, "", "", "", "")
+	h := newHarness(t, d)
+
+	d.SetPercent(10)
+	if !h.press(key.NameEscape) {
+		t.Fatal("Escape didn't cancel the dialog")
+	}
+	if got := result(d); got.Outcome != Canceled {
+		t.Errorf("got %s, want canceled", got.Outcome)
+	}
+}
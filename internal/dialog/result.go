@@ -0,0 +1,67 @@
+package dialog
+
+import (
+	"errors"
+)
+
+var (
+	// ErrCanceled is returned when the user canceled a dialog.
+	ErrCanceled = errors.New("dialog canceled")
+	// ErrDismissed is returned when a dialog was closed without an answer,
+	// e.g. through the window manager.
+	ErrDismissed = errors.New("dialog dismissed")
+	// ErrTimeout is returned when a dialog was closed because its timeout expired.
+	ErrTimeout = errors.New("dialog timed out")
+)
+
+// Outcome describes how a dialog was closed.
+type Outcome int
+
+const (
+	// Dismissed means the dialog was closed without an answer, e.g. through
+	// the window manager.
+	Dismissed Outcome = iota
+	// Confirmed means the user confirmed the dialog.
+	Confirmed
+	// Canceled means the user canceled the dialog.
+	Canceled
+	// TimedOut means the dialog was closed because its timeout expired.
+	TimedOut
+	// Error means the dialog failed, or its context was done.
+	Error
+)
+
+func (o Outcome) String() string {
+	switch o {
+	case Dismissed:
+		return "dismissed"
+	case Confirmed:
+		return "confirmed"
+	case Canceled:
+		return "canceled"
+	case TimedOut:
+		return "timed out"
+	case Error:
+		return "error"
+	default:
+		return "unknown"
+	}
+}
+
+// Result holds how a dialog was closed and the value it produced.
+type Result struct {
+	Outcome Outcome
+	// Value is the entered text or the selected item. It is also set when a
+	// dialog timed out and its TimeoutAction confirmed the default.
+	Value string
+	// Values are the checked items of a multi-select dialog.
+	Values []string
+	// Secret is the password entered in a password dialog.
+	Secret []byte
+	// Fields are the values of the fields of a form dialog, by name.
+	Fields map[string]any
+	// Button is the button that closed the dialog, if any.
+	Button Button
+	// Err is the cause of the Error outcome.
+	Err error
+}
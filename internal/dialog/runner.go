@@ -0,0 +1,269 @@
+package dialog
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"gioui.org/app"
+	"gioui.org/io/event"
+	"gioui.org/io/key"
+	"gioui.org/io/system"
+	"gioui.org/layout"
+	"gioui.org/op"
+	"gioui.org/op/clip"
+	"gioui.org/unit"
+	"gioui.org/widget/material"
+)
+
+// Dialog is implemented by all dialog types of this package.
+//
+// A Dialog only provides its body and its result logic. The label, the
+// description, the OK/Cancel buttons, keyboard shortcuts (Enter confirms,
+// Escape cancels) and the timeout
+// are handled by Run or Modal, which also own the window the dialog is
+// drawn in. Concrete dialogs embed BaseDialog, which provides the common
+// properties and default implementations of all methods.
+type Dialog interface {
+	// base returns the common properties and state of the dialog.
+	base() *BaseDialog
+
+	// open is called once before the first frame. invalidate requests a
+	// new frame from any goroutine and may be nil.
+	open(invalidate func())
+	// close is called once after the dialog was closed.
+	close()
+
+	// initialFocus returns the widget that is focused when the dialog
+	// opens, or nil.
+	initialFocus() event.Tag
+	// update processes the input of the body widgets before the buttons
+	// are handled. It reports whether the user submitted the input from
+	// within the body, e.g. by pressing Enter in a text field.
+	update(gtx layout.Context) (submit bool)
+	// layoutBody lays out the dialog specific widgets between the
+	// description and the buttons.
+	layoutBody(gtx layout.Context, th *material.Theme) layout.Dimensions
+
+	// canConfirm reports whether the OK button is enabled.
+	canConfirm() bool
+	// confirm accepts the current input when the user confirms the
+	// dialog. It returns false to keep the dialog open.
+	confirm() bool
+	// result returns the result once the dialog was closed.
+	result() Result
+}
+
+// Run shows d in a window of its own and runs the event loop until the
+// dialog is closed, or ctx is done.
+func Run(ctx context.Context, d Dialog) Result {
+	if err := ctx.Err(); err != nil {
+		return Result{Outcome: Error, Err: err}
+	}
+
+	b := d.base()
+	w := app.Window{}
+	w.Option(
+		app.Title(b.Title),
+		app.Size(unit.Dp(b.Width), unit.Dp(b.Height)),
+	)
+	// TODO work around https://todo.sr.ht/~eliasnaur/gio/602 (still an issue in gio v0.8.0?)
+	// this should only be required shortly after creating the window w.
+	// It doesn't work with the current gio version (0.8.1-dev), which only includes a fix for os_windows.
+	applyWindowOptions := sync.OnceFunc(func() {
+		time.Sleep(50 * time.Millisecond)
+		w.Perform(system.ActionCenter | system.ActionRaise)
+	})
+	w.Perform(system.ActionCenter | system.ActionRaise)
+	return loop(ctx, &w, d, applyWindowOptions)
+}
+
+// window is the part of app.Window that the event loop uses.
+type window interface {
+	Event() event.Event
+	Perform(actions system.Action)
+	Invalidate()
+}
+
+// loop runs the event loop of d in w until the window is destroyed.
+// beforeFrame is called before every frame.
+func loop(ctx context.Context, w window, d Dialog, beforeFrame func()) Result {
+	// Request a frame once ctx is done, so that the loop notices it.
+	stopWatching := context.AfterFunc(ctx, w.Invalidate)
+	defer stopWatching()
+
+	d.open(w.Invalidate)
+	defer d.close()
+
+	th := material.NewTheme()
+	var ops op.Ops
+
+	for {
+		switch e := w.Event().(type) {
+		case app.FrameEvent:
+			beforeFrame()
+			gtx := app.NewContext(&ops, e)
+			if ctx.Err() != nil {
+				w.Perform(system.ActionClose)
+			} else if frame(gtx, th, d) {
+				w.Perform(system.ActionClose)
+			}
+			e.Frame(gtx.Ops)
+		case app.DestroyEvent:
+			if err := ctx.Err(); err != nil {
+				return Result{Outcome: Error, Err: err}
+			}
+			if e.Err != nil {
+				return Result{Outcome: Error, Err: e.Err}
+			}
+			return result(d)
+		}
+	}
+}
+
+// frame handles the user input of the current frame and lays out d. It
+// reports whether the user closed the dialog, or its timeout expired.
+func frame(gtx layout.Context, th *material.Theme, d Dialog) bool {
+	b := d.base()
+	defer clip.Rect{Max: gtx.Constraints.Max}.Push(gtx.Ops).Pop()
+	if b.nested != nil {
+		return frameNested(gtx, th, d)
+	}
+	b.countdown.watch(gtx)
+	if !b.focused {
+		b.focused = true
+		if tag := d.initialFocus(); tag != nil {
+			gtx.Execute(key.FocusCmd{Tag: tag})
+		}
+	}
+	submit := d.update(gtx)
+
+	pressed := -1
+	for i := range b.buttonClicks {
+		if b.buttonClicks[i].Clicked(gtx) {
+			pressed = i
+		}
+	}
+	cancel := false
+	// Enter and Escape are only delivered here if no focused widget, such
+	// as an editor or a button, handles them itself.
+	for {
+		ev, ok := gtx.Event(
+			key.Filter{Name: key.NameEscape},
+			key.Filter{Name: key.NameReturn},
+			key.Filter{Name: key.NameEnter},
+		)
+		if !ok {
+			break
+		}
+		if e, ok := ev.(key.Event); ok && e.State == key.Press {
+			if e.Name == key.NameEscape {
+				cancel = true
+			} else {
+				submit = true
+			}
+		}
+	}
+
+	// Enter presses the default button, and Escape the cancel button.
+	if cancel {
+		pressed = b.buttonIndex(func(btn Button) bool { return btn.Cancel })
+	} else if submit && pressed < 0 {
+		pressed = b.buttonIndex(func(btn Button) bool { return btn.Default })
+	}
+
+	closed := false
+	switch {
+	case pressed >= 0:
+		btn := b.buttons[pressed]
+		if !btn.accepts() {
+			b.outcome = Canceled
+			b.pressed = btn
+			closed = true
+		} else if d.confirm() {
+			b.outcome = Confirmed
+			b.pressed = btn
+			closed = true
+		} else if b.nested != nil {
+			// confirm asks first; move the keyboard focus to the question
+			b.askedBy = pressed
+			gtx.Execute(key.FocusCmd{})
+		}
+	case cancel:
+		b.outcome = Canceled
+		closed = true
+	}
+	if !closed && b.finished {
+		closed = true
+	}
+	if !closed && b.countdown.expired(gtx) {
+		// A dialog whose input can't be confirmed times out without a value.
+		if b.TimeoutAction == TimeoutConfirm {
+			d.confirm()
+		}
+		b.outcome = TimedOut
+		closed = true
+	}
+	layoutDialog(gtx, th, d)
+	return closed
+}
+
+// frameNested draws d without processing its input, and the dialog it
+// asked on top of it. Once the nested dialog is closed, the button press
+// that opened it may be repeated, which can close d.
+func frameNested(gtx layout.Context, th *material.Theme, d Dialog) bool {
+	b := d.base()
+	layoutDialog(gtx.Disabled(), th, d)
+	if _, closed := b.nested.Layout(gtx, th); !closed {
+		return false
+	}
+	repeat := b.nestedDone(b.nested.Result())
+	pressed := b.askedBy
+	b.nested, b.nestedDone = nil, nil
+	// Focus the initial widget of d again
+	b.focused = false
+	if repeat && pressed >= 0 && d.confirm() {
+		b.outcome = Confirmed
+		b.pressed = b.buttons[pressed]
+		return true
+	}
+	return false
+}
+
+// result returns the result of d, including the button that closed it.
+func result(d Dialog) Result {
+	r := d.result()
+	r.Button = d.base().pressed
+	return r
+}
+
+// layoutDialog lays out the label, description, body and buttons of d.
+func layoutDialog(gtx layout.Context, th *material.Theme, d Dialog) layout.Dimensions {
+	b := d.base()
+	return layout.UniformInset(unit.Dp(20)).Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+		return layout.Flex{Axis: layout.Vertical, Spacing: layout.SpaceAround}.Layout(gtx,
+			// Label
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				label := material.H6(th, b.Label)
+				return label.Layout(gtx)
+			}),
+			// Description
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				if b.Description == "" {
+					return layout.Dimensions{}
+				}
+				desc := material.Body1(th, b.Description)
+				desc.Color = th.Fg
+				return desc.Layout(gtx)
+			}),
+			// Dialog specific widgets
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return d.layoutBody(gtx, th)
+			}),
+			// Buttons
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return layoutButtons(gtx, th, d)
+			}),
+		)
+	})
+}
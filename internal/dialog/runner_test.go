@@ -0,0 +1,210 @@
+package dialog
+
+import (
+	"errors"
+	"image"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"gioui.org/io/input"
+	"gioui.org/io/key"
+	"gioui.org/layout"
+	"gioui.org/op"
+	"gioui.org/unit"
+	"gioui.org/widget"
+	"gioui.org/widget/material"
+)
+
+// harness drives a Dialog frame by frame through an input.Router, so that
+// tests can inject key events the way a window would deliver them.
+type harness struct {
+	t      *testing.T
+	d      Dialog
+	modal  *Modal // lays out d instead of frame, if set
+	th     *material.Theme
+	router input.Router
+	ops    op.Ops
+	now    time.Time
+	closed bool
+}
+
+func newHarness(t *testing.T, d Dialog) *harness {
+	t.Helper()
+	h := &harness{t: t, d: d, th: material.NewTheme(), now: time.Now()}
+	d.open(nil)
+	t.Cleanup(d.close)
+	// The first frame registers the event handlers and focuses the
+	// initial widget, the second one applies the focus.
+	h.frame()
+	h.frame()
+	return h
+}
+
+// frame lays out one frame and reports whether the dialog closed.
+func (h *harness) frame() bool {
+	h.t.Helper()
+	if h.closed {
+		h.t.Fatal("frame after the dialog was closed")
+	}
+	h.ops.Reset()
+	h.now = h.now.Add(16 * time.Millisecond)
+	gtx := layout.Context{
+		Ops:         &h.ops,
+		Now:         h.now,
+		Metric:      unit.Metric{PxPerDp: 1, PxPerSp: 1},
+		Constraints: layout.Exact(image.Pt(400, 300)),
+		Source:      h.router.Source(),
+	}
+	if h.modal != nil {
+		_, h.closed = h.modal.Layout(gtx, h.th)
+	} else {
+		h.closed = frame(gtx, h.th, h.d)
+	}
+	h.router.Frame(&h.ops)
+	return h.closed
+}
+
+// press injects a key press and processes it in a new frame.
+func (h *harness) press(name key.Name) bool {
+	h.t.Helper()
+	h.router.Queue(
+		key.Event{Name: name, State: key.Press},
+		key.Event{Name: name, State: key.Release},
+	)
+	return h.frame()
+}
+
+// typeText appends text to the content of the focused widget.
+func (h *harness) typeText(text string) {
+	h.t.Helper()
+	// Editors clamp the range to their content.
+	end := key.Range{Start: math.MaxInt32, End: math.MaxInt32}
+	h.router.Queue(key.EditEvent{Range: end, Text: text})
+	h.frame()
+}
+
+// typeInto replaces the selection of e with text and moves the caret
+// after it, like a platform input method does while e has the focus.
+func (h *harness) typeInto(e *widget.Editor, text string) {
+	h.t.Helper()
+	start, end := e.Selection()
+	caret := start + utf8.RuneCountInString(text)
+	h.router.Queue(
+		key.EditEvent{Range: key.Range{Start: start, End: end}, Text: text},
+		key.SelectionEvent{Start: caret, End: caret},
+	)
+	h.frame()
+}
+
+func TestKeys(t *testing.T) {
+	errShort := errors.New("too short")
+	validate := func(s string) error {
+		if len(s) < 3 {
+			return errShort
+		}
+		return nil
+	}
+
+	tests := []struct {
+		name   string
+		dialog func() Dialog
+		keys   []key.Name
+		want   Result
+	}{
+		{
+			name:   "base/return",
+			dialog: func() Dialog { return NewBaseDialog(0, 0, "", "", "") },
+			keys:   []key.Name{key.NameReturn},
+			want:   Result{Outcome: Confirmed},
+		},
+		{
+			name:   "base/enter",
+			dialog: func() Dialog { return NewBaseDialog(0, 0, "", "", "") },
+			keys:   []key.Name{key.NameEnter},
+			want:   Result{Outcome: Confirmed},
+		},
+		{
+			name:   "base/escape",
+			dialog: func() Dialog { return NewBaseDialog(0, 0, "", "", "") },
+			keys:   []key.Name{key.NameEscape},
+			want:   Result{Outcome: Canceled},
+		},
+		{
+			name:   "input/return",
+			dialog: func() Dialog { return NewInputDialog(0, 0, "", "", "", "hello", validate) },
+			keys:   []key.Name{key.NameReturn},
+			want:   Result{Outcome: Confirmed, Value: "hello"},
+		},
+		{
+			name:   "input/escape",
+			dialog: func() Dialog { return NewInputDialog(0, 0, "", "", "", "hello", validate) },
+			keys:   []key.Name{key.NameEscape},
+			want:   Result{Outcome: Canceled},
+		},
+		{
+			name:   "select/return",
+			dialog: func() Dialog { return NewSelectDialog(0, 0, "", "", "", []string{"a", "b"}, "b", false) },
+			keys:   []key.Name{key.NameReturn},
+			want:   Result{Outcome: Confirmed, Value: "b"},
+		},
+		{
+			name:   "select/escape",
+			dialog: func() Dialog { return NewSelectDialog(0, 0, "", "", "", []string{"a", "b"}, "b", true) },
+			keys:   []key.Name{key.NameEscape},
+			want:   Result{Outcome: Canceled},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := tt.dialog()
+			h := newHarness(t, d)
+			for _, k := range tt.keys {
+				if !h.press(k) {
+					t.Fatalf("dialog still open after %s", k)
+				}
+			}
+			if got := d.result(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInputKeysRespectValidation(t *testing.T) {
+	d := NewInputDialog(0, 0, "", "", "", "", func(s string) error {
+		if s == "" {
+			return errors.New("required")
+		}
+		return nil
+	})
+	h := newHarness(t, d)
+
+	if h.press(key.NameReturn) {
+		t.Fatal("Enter confirmed invalid input")
+	}
+	h.typeText("gio")
+	if !h.press(key.NameReturn) {
+		t.Fatal("Enter didn't confirm valid input")
+	}
+	if want := (Result{Outcome: Confirmed, Value: "gio"}); !reflect.DeepEqual(d.result(), want) {
+		t.Errorf("got %+v, want %+v", d.result(), want)
+	}
+}
+
+func TestSelectCustomEntrySubmit(t *testing.T) {
+	d := NewSelectDialog(0, 0, "", "", "", []string{"a", "b"}, "a", true)
+	h := newHarness(t, d)
+
+	h.router.Source().Execute(key.FocusCmd{Tag: &d.customInput})
+	h.frame()
+	h.typeText("custom")
+	if !h.press(key.NameReturn) {
+		t.Fatal("Enter in the custom entry didn't confirm the dialog")
+	}
+	if want := (Result{Outcome: Confirmed, Value: "custom"}); !reflect.DeepEqual(d.result(), want) {
+		t.Errorf("got %+v, want %+v", d.result(), want)
+	}
+}
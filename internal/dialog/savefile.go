@@ -0,0 +1,311 @@
+package dialog
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	"gioui.org/io/event"
+	"gioui.org/layout"
+	"gioui.org/unit"
+	"gioui.org/widget"
+	"gioui.org/widget/material"
+)
+
+// MkdirFS is a file system in which directories can be created. File
+// dialogs offer a "New folder" action for such file systems.
+type MkdirFS interface {
+	fs.FS
+	Mkdir(name string, perm fs.FileMode) error
+}
+
+// saveFileDialog is the internal implementation of a save file dialog: a
+// file browser with a field for the file name. It asks before returning
+// a file that already exists.
+type saveFileDialog struct {
+	BaseDialog
+	// Browser lists the directories and files, and holds the file
+	// system, the initial directory and the filters.
+	Browser fileBrowser
+	// Extension, e.g. ".txt", is appended to file names that don't end
+	// with it. Without it, the extension of the active filter is used if
+	// its first pattern is like "*.txt".
+	Extension string
+
+	// internal result state
+	path string
+	// overwrite is the path of the existing file the user agreed to
+	// replace.
+	overwrite string
+	err       error
+
+	// UI state
+	nameInput       widget.Editor
+	newFolderButton widget.Clickable
+	// preset tells update to ignore the change of the initial name.
+	preset bool
+}
+
+// NewSaveFileDialog initializes a saveFileDialog from provided parameters.
+// dir is the directory shown first, as a path of fsys, and name the file
+// name suggested.
+func NewSaveFileDialog(width, height float32, title, label, description string, fsys fs.FS, dir, name string, filters []FileFilter) *saveFileDialog {
+	if width <= 0 {
+		width = 560
+	}
+	if height <= 0 {
+		height = 520
+	}
+	d := &saveFileDialog{
+		BaseDialog: *NewBaseDialog(width, height, title, label, description),
+	}
+	d.Buttons = []Button{
+		{Label: "Cancel", Role: RoleNegative, Cancel: true},
+		{Label: "Save", Role: RoleAffirmative, Default: true},
+	}
+	d.Browser.FS, d.Browser.Dir, d.Browser.Filters = fsys, dir, filters
+	d.nameInput.SingleLine = true
+	d.nameInput.Submit = true
+	d.preset = setInitialText(&d.nameInput, name)
+	return d
+}
+
+// open reads the initial directory, and selects the name without its
+// extension, so that typing replaces it.
+func (d *saveFileDialog) open(invalidate func()) {
+	d.BaseDialog.open(invalidate)
+	d.Browser.load(d.Browser.Dir)
+	if err := d.Browser.err; err != nil {
+		d.Browser.load(".")
+		d.Browser.err = err
+	}
+	name := d.nameInput.Text()
+	d.nameInput.SetCaret(utf8.RuneCountInString(strings.TrimSuffix(name, path.Ext(name))), 0)
+}
+
+func (d *saveFileDialog) initialFocus() event.Tag { return &d.nameInput }
+
+// update opens directories on click, and copies the names of files into
+// the name field. A double click or Enter on a file saves it.
+func (d *saveFileDialog) update(gtx layout.Context) bool {
+	submit := false
+	i, activate, changed := d.Browser.update(gtx)
+	if changed {
+		d.countdown.pause()
+	}
+	if i >= 0 {
+		d.countdown.pause()
+		d.err = nil
+		e := d.Browser.entries[i]
+		if e.dir {
+			d.Browser.load(d.Browser.path(e.name))
+		} else {
+			d.nameInput.SetText(e.name)
+			submit = activate
+		}
+	}
+
+	for {
+		ev, ok := d.nameInput.Update(gtx)
+		if !ok {
+			break
+		}
+		switch ev.(type) {
+		case widget.ChangeEvent:
+			if d.preset {
+				d.preset = false
+				break
+			}
+			d.countdown.pause()
+			d.err = nil
+		case widget.SubmitEvent:
+			submit = true
+		}
+	}
+
+	if fsys, ok := d.Browser.FS.(MkdirFS); ok && d.newFolderButton.Clicked(gtx) {
+		d.askNewFolder(fsys, d.Browser.Dir, func(dir string, err error) {
+			if err != nil {
+				d.Browser.err = err
+				return
+			}
+			d.Browser.load(dir)
+		})
+	}
+	return submit
+}
+
+// askNewFolder asks for the name of a new folder in the directory parent
+// of fsys and creates it. created is called with its path, or the error.
+func (b *BaseDialog) askNewFolder(fsys MkdirFS, parent string, created func(dir string, err error)) {
+	input := NewInputDialog(360, 0, "New Folder", "Folder name", "", "", func(name string) error {
+		if err := validName(name); err != nil {
+			return err
+		}
+		if _, err := fs.Stat(fsys, path.Join(parent, name)); err == nil {
+			return fmt.Errorf("%q already exists", name)
+		}
+		return nil
+	})
+	input.Buttons = []Button{
+		{Label: "Cancel", Role: RoleNegative, Cancel: true},
+		{Label: "Create", Role: RoleAffirmative, Default: true},
+	}
+	b.ask(input, func(r Result) bool {
+		if r.Outcome == Confirmed {
+			dir := path.Join(parent, r.Value)
+			created(dir, fsys.Mkdir(dir, 0o755))
+		}
+		return false
+	})
+}
+
+// fileName returns the entered name with the extension.
+func (d *saveFileDialog) fileName() string {
+	name := strings.TrimSpace(d.nameInput.Text())
+	ext := d.Extension
+	if ext == "" && len(d.Browser.Filters) > 0 {
+		ext = patternExt(d.Browser.Filters[d.Browser.filter])
+	}
+	if name == "" || ext == "" || strings.HasSuffix(strings.ToLower(name), strings.ToLower(ext)) {
+		return name
+	}
+	return name + ext
+}
+
+// patternExt returns the extension of the first pattern of f if it
+// matches all files with that extension, like "*.txt".
+func patternExt(f FileFilter) string {
+	if len(f.Patterns) == 0 {
+		return ""
+	}
+	ext, ok := strings.CutPrefix(f.Patterns[0], "*")
+	if !ok || !strings.HasPrefix(ext, ".") || strings.ContainsAny(ext, `*?[\`) {
+		return ""
+	}
+	return ext
+}
+
+// validName checks that name is the name of a file, without a directory.
+func validName(name string) error {
+	switch {
+	case name == "":
+		return errors.New("enter a name")
+	case name == "." || name == ".." || strings.ContainsAny(name, `/\`):
+		return errors.New("enter a name without a folder")
+	}
+	return nil
+}
+
+// insideRoot checks that the path p of the operating system resolves to a
+// location inside root, also through symbolic links.
+func insideRoot(root, p string) error {
+	root, err := filepath.EvalSymlinks(root)
+	if err != nil {
+		return err
+	}
+	// The file may not exist yet, but its directory does
+	resolved, err := filepath.EvalSymlinks(p)
+	if errors.Is(err, fs.ErrNotExist) {
+		resolved, err = filepath.EvalSymlinks(filepath.Dir(p))
+		resolved = filepath.Join(resolved, filepath.Base(p))
+	}
+	if err != nil {
+		return err
+	}
+	if rel, err := filepath.Rel(root, resolved); err != nil || !filepath.IsLocal(rel) {
+		return fmt.Errorf("%s is outside of %s", p, root)
+	}
+	return nil
+}
+
+func (d *saveFileDialog) layoutBody(gtx layout.Context, th *material.Theme) layout.Dimensions {
+	_, mkdir := d.Browser.FS.(MkdirFS)
+	name := d.fileName()
+	return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+		layout.Flexed(1, func(gtx layout.Context) layout.Dimensions {
+			// Highlight the file that would be replaced
+			return d.Browser.layout(gtx, th, func(e fileEntry) bool { return !e.dir && e.name == name })
+		}),
+		// File name and new folder action
+		layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+			return layout.Inset{Top: unit.Dp(6)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+				return layout.Flex{Axis: layout.Horizontal, Alignment: layout.Middle}.Layout(gtx,
+					layout.Rigid(material.Body1(th, "Name: ").Layout),
+					layout.Flexed(1, func(gtx layout.Context) layout.Dimensions {
+						return styledEditor(gtx, th, &d.nameInput, "File name")
+					}),
+					layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+						if !mkdir {
+							return layout.Dimensions{}
+						}
+						return flatButton(gtx, th, &d.newFolderButton, "New folder")
+					}),
+				)
+			})
+		}),
+		layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+			if d.err == nil {
+				return layout.Dimensions{}
+			}
+			msg := material.Caption(th, d.err.Error())
+			msg.Color = errorColor
+			return msg.Layout(gtx)
+		}),
+	)
+}
+
+// canConfirm reports whether a file name is entered.
+func (d *saveFileDialog) canConfirm() bool {
+	return strings.TrimSpace(d.nameInput.Text()) != ""
+}
+
+// confirm accepts the file, asking first if it exists.
+func (d *saveFileDialog) confirm() bool {
+	name := d.fileName()
+	if d.err = validName(name); d.err != nil {
+		return false
+	}
+	p := d.Browser.path(name)
+	if d.Browser.Root != "" {
+		if d.err = insideRoot(d.Browser.Root, d.Browser.osPath(p)); d.err != nil {
+			return false
+		}
+	}
+	if info, err := fs.Stat(d.Browser.FS, p); err == nil && p != d.overwrite {
+		if info.IsDir() {
+			d.err = fmt.Errorf("%q is a folder", name)
+			return false
+		}
+		d.askOverwrite(name, p)
+		return false
+	}
+	d.path = d.Browser.osPath(p)
+	return true
+}
+
+// askOverwrite asks whether the existing file p may be replaced.
+func (d *saveFileDialog) askOverwrite(name, p string) {
+	question := NewBaseDialog(360, 160, "Replace File",
+		fmt.Sprintf("%q already exists.", name),
+		"Do you want to replace it?")
+	question.Buttons = []Button{
+		{Label: "Cancel", Role: RoleNegative, Cancel: true},
+		{Label: "Replace", Role: RoleDestructive, Default: true},
+	}
+	d.ask(question, func(r Result) bool {
+		if r.Outcome != Confirmed {
+			return false
+		}
+		d.overwrite = p
+		return true
+	})
+}
+
+func (d *saveFileDialog) result() Result {
+	return Result{Outcome: d.outcome, Value: d.path}
+}
@@ -0,0 +1,153 @@
+package dialog
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"gioui.org/io/key"
+)
+
+// mkdirFS is a MapFS in which directories can be created.
+type mkdirFS struct{ fstest.MapFS }
+
+func (m mkdirFS) Mkdir(name string, perm fs.FileMode) error {
+	m.MapFS[name] = &fstest.MapFile{Mode: fs.ModeDir | perm}
+	return nil
+}
+
+func TestSaveFile(t *testing.T) {
+	tests := []struct {
+		name      string
+		dir       string
+		file      string
+		extension string
+		filters   []FileFilter
+		want      string
+	}{
+		{"plain", ".", "notes", "", nil, "notes"},
+		{"directory", "docs", "notes", "", nil, "docs/notes"},
+		{"extension", ".", "notes", ".txt", nil, "notes.txt"},
+		{"extension present", ".", "notes.TXT", ".txt", nil, "notes.TXT"},
+		{"filter extension", ".", "notes", "", []FileFilter{{Patterns: []string{"*.md", "*.txt"}}}, "notes.md"},
+		{"filter without extension", ".", "notes", "", []FileFilter{{Patterns: []string{"*"}}}, "notes"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := NewSaveFileDialog(0, 0, "", "", "", testFS(), tt.dir, tt.file, tt.filters)
+			d.Extension = tt.extension
+			h := newHarness(t, d)
+			if !h.press(key.NameReturn) {
+				t.Fatal("Enter didn't confirm the dialog")
+			}
+			if got := result(d); got.Outcome != Confirmed || got.Value != tt.want {
+				t.Errorf("got %s %q, want %q", got.Outcome, got.Value, tt.want)
+			}
+		})
+	}
+}
+
+func TestSaveFileInvalidName(t *testing.T) {
+	for _, name := range []string{"docs/x", `..\x`, ".."} {
+		d := NewSaveFileDialog(0, 0, "", "", "", testFS(), ".", name, nil)
+		h := newHarness(t, d)
+		if h.press(key.NameReturn) || d.err == nil {
+			t.Errorf("%q: the dialog accepted an invalid name", name)
+		}
+	}
+}
+
+func TestSaveFileOverwrite(t *testing.T) {
+	d := NewSaveFileDialog(0, 0, "", "", "", testFS(), ".", "README.md", nil)
+	h := newHarness(t, d)
+
+	if h.press(key.NameReturn) || d.nested == nil {
+		t.Fatal("saving an existing file didn't ask first")
+	}
+	if h.press(key.NameEscape) {
+		t.Fatal("declining to replace the file closed the dialog")
+	}
+	if d.nested != nil {
+		t.Fatal("the question is still shown")
+	}
+	// The name field has the focus again
+	h.frame()
+	if h.press(key.NameReturn) || d.nested == nil {
+		t.Fatal("saving the existing file again didn't ask")
+	}
+	if !h.press(key.NameReturn) {
+		t.Fatal("agreeing to replace the file didn't close the dialog")
+	}
+	if got := result(d); got.Outcome != Confirmed || got.Value != "README.md" || got.Button.Label != "Save" {
+		t.Errorf("got %+v, want README.md confirmed by Save", got)
+	}
+}
+
+func TestSaveFileNewFolder(t *testing.T) {
+	fsys := mkdirFS{testFS()}
+	d := NewSaveFileDialog(0, 0, "", "", "", fsys, "docs", "notes", nil)
+	h := newHarness(t, d)
+
+	h.router.Source().Execute(key.FocusCmd{Tag: &d.newFolderButton})
+	h.frame()
+	h.press(key.NameSpace)
+	if d.nested == nil {
+		t.Fatal("New folder didn't ask for a name")
+	}
+	// The first frame focuses the name field of the question
+	h.frame()
+	h.typeText("drafts")
+	if h.press(key.NameReturn) {
+		t.Fatal("creating a folder closed the dialog")
+	}
+	if info, err := fs.Stat(fsys, "docs/drafts"); err != nil || !info.IsDir() {
+		t.Fatalf("the folder wasn't created: %v", err)
+	}
+	if d.Browser.Dir != "docs/drafts" {
+		t.Errorf("got directory %q, want the new folder", d.Browser.Dir)
+	}
+}
+
+func TestSaveFileOutsideRoot(t *testing.T) {
+	root, outside := t.TempDir(), t.TempDir()
+	if err := os.Symlink(outside, filepath.Join(root, "link")); err != nil {
+		t.Skip("symbolic links aren't supported:", err)
+	}
+
+	d := NewSaveFileDialog(0, 0, "", "", "", os.DirFS(root), "link", "notes.txt", nil)
+	d.Browser.Root = root
+	h := newHarness(t, d)
+	if d.Browser.Dir != "link" {
+		t.Fatalf("got directory %q, want link", d.Browser.Dir)
+	}
+	if h.press(key.NameReturn) || d.err == nil {
+		t.Fatal("saved a file outside of the root")
+	}
+
+	d = NewSaveFileDialog(0, 0, "", "", "", os.DirFS(root), ".", "notes.txt", nil)
+	d.Browser.Root = root
+	h = newHarness(t, d)
+	if !h.press(key.NameReturn) {
+		t.Fatalf("saving inside the root failed: %v", d.err)
+	}
+	if want := filepath.Join(root, "notes.txt"); d.result().Value != want {
+		t.Errorf("got %q, want %q", d.result().Value, want)
+	}
+}
+
+func TestPatternExt(t *testing.T) {
+	for pattern, want := range map[string]string{
+		"*.txt":    ".txt",
+		"*.tar.gz": ".tar.gz",
+		"*":        "",
+		"*.[ch]":   "",
+		"a*.txt":   "",
+		"*txt":     "",
+	} {
+		if got := patternExt(FileFilter{Patterns: []string{pattern}}); got != want {
+			t.Errorf("%s: got %q, want %q", pattern, got, want)
+		}
+	}
+}
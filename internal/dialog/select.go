@@ -1,61 +1,66 @@
 package dialog
 
 import (
-	"image"
 	"image/color"
-	"sync"
+	"slices"
+	"strings"
 	"time"
 
-	"gioui.org/app"
+	"gioui.org/font"
+	"gioui.org/io/event"
 	"gioui.org/io/key"
-	"gioui.org/io/system"
 	"gioui.org/layout"
-	"gioui.org/op"
 	"gioui.org/op/clip"
-	"gioui.org/op/paint"
 	"gioui.org/unit"
 	"gioui.org/widget"
 	"gioui.org/widget/material"
 )
 
+// typeAheadTimeout is the pause after which type-ahead starts a new search.
+const typeAheadTimeout = time.Second
+
 // selectDialog is the internal implementation stub for a single-select dialog.
 type selectDialog struct {
-	Width, Height    float32
-	Title            string
-	Label            string
-	Description      string
+	BaseDialog
 	Choices          []string
 	DefaultSelection string
 	AllowCustomEntry bool
 
+	// Searchable shows a search field above the list that narrows the
+	// choices as the user types, matched according to SearchMode.
+	Searchable bool
+	SearchMode MatchMode
+
 	// internal result state
 	selected string
-	canceled bool
 
 	// UI state
 	selectedIndex int
 	choiceButtons []widget.Clickable
 	customInput   widget.Editor
-	okButton      widget.Clickable
-	cancelButton  widget.Clickable
 	list          widget.List
-	done          bool
+
+	// Search state: filtered holds the indexes of the choices shown in
+	// the list, i.e. all of them as long as query is empty.
+	search   widget.Editor
+	matcher  *matcher
+	query    string
+	all      []int
+	filtered []int
+
+	// Type-ahead state: the letters typed so far, and when the last one
+	// was typed.
+	typed   string
+	typedAt time.Time
 }
 
 // NewSelectDialog initializes a selectDialog from provided parameters.
 func NewSelectDialog(width, height float32, title, label, description string, choices []string, defaultSelection string, allowCustomEntry bool) *selectDialog {
-	if width <= 0 {
-		width = 400
-	}
 	if height <= 0 {
 		height = 300
 	}
 	d := &selectDialog{
-		Width:            width,
-		Height:           height,
-		Title:            title,
-		Label:            label,
-		Description:      description,
+		BaseDialog:       *NewBaseDialog(width, height, title, label, description),
 		Choices:          choices,
 		DefaultSelection: defaultSelection,
 		AllowCustomEntry: allowCustomEntry,
@@ -64,6 +69,11 @@ func NewSelectDialog(width, height float32, title, label, description string, ch
 
 	// Initialize clickable buttons for each choice
 	d.choiceButtons = make([]widget.Clickable, len(choices))
+	d.all = make([]int, len(choices))
+	for i := range d.all {
+		d.all[i] = i
+	}
+	d.filtered = d.all
 
 	// Set default selection
 	for i, choice := range choices {
@@ -76,6 +86,7 @@ func NewSelectDialog(width, height float32, title, label, description string, ch
 	// Initialize custom input if allowed
 	if allowCustomEntry {
 		d.customInput.SingleLine = true
+		d.customInput.Submit = true
 	}
 
 	// Initialize scrollable list
@@ -84,192 +95,281 @@ func NewSelectDialog(width, height float32, title, label, description string, ch
 	return d
 }
 
-// styledEditor creates an editor with border styling inspired by cu theme
-func (d *selectDialog) styledEditor(gtx layout.Context, th *material.Theme) layout.Dimensions {
-	cornerRadius := unit.Dp(4)
-	inset := unit.Dp(4)
-
-	// Set minimum size for input field
-	minWidth := unit.Dp(200)
-	minHeight := unit.Dp(32)
-
-	// Apply minimum constraints
-	gtx.Constraints.Min.X = max(gtx.Constraints.Min.X, gtx.Dp(minWidth))
-	gtx.Constraints.Min.Y = max(gtx.Constraints.Min.Y, gtx.Dp(minHeight))
-
-	return layout.Stack{Alignment: layout.W}.Layout(gtx,
-		// Draw the background and border
-		layout.Expanded(func(gtx layout.Context) layout.Dimensions {
-			rect := image.Rectangle{Max: gtx.Constraints.Min}
-			inner := rect.Inset(gtx.Dp(inset))
-
-			// Colors inspired by cu theme
-			backgroundColor := color.NRGBA{R: 255, G: 255, B: 255, A: 255}
-			borderColor := color.NRGBA{R: 200, G: 200, B: 200, A: 255}
-			focusColor := color.NRGBA{R: 0, G: 123, B: 255, A: 255}
-
-			rr := gtx.Dp(cornerRadius)
-
-			// Draw focus border if focused
-			if gtx.Focused(&d.customInput) {
-				w := gtx.Dp(2)
-				paint.FillShape(gtx.Ops, focusColor,
-					clip.Stroke{
-						Path:  clip.UniformRRect(rect.Inset(w), rr+w).Path(gtx.Ops),
-						Width: float32(w),
-					}.Op(),
-				)
-			}
-
-			// Draw background
-			shape := clip.UniformRRect(inner, rr)
-			defer shape.Push(gtx.Ops).Pop()
-			paint.Fill(gtx.Ops, backgroundColor)
-
-			// Draw border
-			w := gtx.Dp(1)
-			paint.FillShape(gtx.Ops, borderColor,
-				clip.Stroke{
-					Path:  clip.UniformRRect(inner, rr).Path(gtx.Ops),
-					Width: float32(w),
-				}.Op(),
-			)
-
-			return layout.Dimensions{Size: gtx.Constraints.Min}
-		}),
+// open prepares the timeout and the search field before the dialog is
+// shown.
+func (d *selectDialog) open(invalidate func()) {
+	d.BaseDialog.open(invalidate)
+	if d.Searchable {
+		d.search.SingleLine = true
+		d.search.Submit = true
+		d.matcher = newMatcher(d.SearchMode, d.Choices)
+	}
+}
 
-		// Draw the text editor on top
-		layout.Stacked(func(gtx layout.Context) layout.Dimensions {
-			// Apply minimum constraints to the editor
-			gtx.Constraints.Min.X = gtx.Dp(minWidth)
-			gtx.Constraints.Min.Y = gtx.Dp(minHeight)
-
-			return layout.Inset{
-				Top:    8,
-				Bottom: 8,
-				Left:   12,
-				Right:  12,
-			}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
-				editor := material.Editor(th, &d.customInput, "")
-				editor.TextSize = unit.Sp(14)
-				return editor.Layout(gtx)
-			})
-		}),
-	)
+// initialFocus focuses the search field, if any, or the list, which is
+// the focus tag for keyboard navigation of the choices.
+func (d *selectDialog) initialFocus() event.Tag {
+	if d.Searchable {
+		return &d.search
+	}
+	return d
 }
 
-// Show runs the single-selection dialog event loop and returns the selected
-// item, a canceled flag, and an error if something went wrong.
-func (d *selectDialog) Show() (string, bool, error) {
-	w := app.Window{}
-	w.Option(
-		app.Title(d.Title),
-		app.Size(unit.Dp(d.Width), unit.Dp(d.Height)),
-	)
-	// TODO work around https://todo.sr.ht/~eliasnaur/gio/602 (still an issue in gio v0.8.0?)
-	// this should only be required shortly after creating the window w.
-	// It doesn't work with the current gio version (0.8.1-dev), which only includes a fix for os_windows.
-	applyWindowOptions := sync.OnceFunc(func() {
-		time.Sleep(50 * time.Millisecond)
-		w.Perform(system.ActionCenter | system.ActionRaise)
-	})
-	w.Perform(system.ActionCenter | system.ActionRaise)
-
-	th := material.NewTheme()
-	var ops op.Ops
-
-	for !d.done {
-		switch e := w.Event().(type) {
-		case app.FrameEvent:
-			applyWindowOptions()
-			gtx := app.NewContext(&ops, e)
-			if d.cancelButton.Clicked(gtx) {
-				d.handleCancel()
-				w.Perform(system.ActionClose)
+// update processes the events of the search field, the custom entry and
+// the keyboard navigation of the list. Enter in the search field or the
+// custom entry submits the dialog, in the search field only once a choice
+// is selected or a custom value entered.
+func (d *selectDialog) update(gtx layout.Context) bool {
+	submit := false
+	if d.Searchable {
+		// The list can be navigated while typing a search query. These
+		// keys have to be taken before the editor handles them.
+		for {
+			ev, ok := gtx.Event(
+				key.Filter{Focus: &d.search, Name: key.NameUpArrow},
+				key.Filter{Focus: &d.search, Name: key.NameDownArrow},
+				key.Filter{Focus: &d.search, Name: key.NamePageUp},
+				key.Filter{Focus: &d.search, Name: key.NamePageDown},
+			)
+			if !ok {
+				break
+			}
+			if e, ok := ev.(key.Event); ok && e.State == key.Press {
+				d.countdown.pause()
+				d.navigate(e.Name)
 			}
-			if d.okButton.Clicked(gtx) {
-				d.handleOK()
-				w.Perform(system.ActionClose)
+		}
+		for {
+			ev, ok := d.search.Update(gtx)
+			if !ok {
+				break
+			}
+			switch ev.(type) {
+			case widget.ChangeEvent:
+				d.countdown.pause()
+				d.applyFilter(d.search.Text())
+			case widget.SubmitEvent:
+				// Without a match, Enter would confirm an empty value.
+				submit = d.selectedIndex >= 0 || d.customInput.Text() != ""
 			}
-			d.layout(gtx, th)
-			e.Frame(gtx.Ops)
+		}
+	}
+
+	for {
+		ev, ok := d.customInput.Update(gtx)
+		if !ok {
+			break
+		}
+		switch ev.(type) {
+		case widget.ChangeEvent:
+			d.countdown.pause()
+		case widget.SubmitEvent:
+			submit = true
+		}
+	}
+
+	for {
+		ev, ok := gtx.Event(
+			key.FocusFilter{Target: d},
+			key.Filter{Focus: d, Name: key.NameUpArrow},
+			key.Filter{Focus: d, Name: key.NameDownArrow},
+			key.Filter{Focus: d, Name: key.NameHome},
+			key.Filter{Focus: d, Name: key.NameEnd},
+			key.Filter{Focus: d, Name: key.NamePageUp},
+			key.Filter{Focus: d, Name: key.NamePageDown},
+		)
+		if !ok {
+			break
+		}
+		switch e := ev.(type) {
 		case key.Event:
-			// Handle Escape key for cancel
-			if e.Name == key.NameEscape && e.State == key.Press {
-				d.handleCancel()
-				w.Perform(system.ActionClose)
+			if e.State == key.Press {
+				d.countdown.pause()
+				d.navigate(e.Name)
 			}
-		case app.DestroyEvent:
-			d.done = true
-			return d.selected, d.canceled, e.Err
+		case key.EditEvent:
+			d.countdown.pause()
+			d.typeAhead(gtx.Now, e.Text)
 		}
 	}
-	//app.Main()
-	return d.selected, d.canceled, nil
+
+	// Tab only cycles through the search field, the list, the custom entry
+	// and the buttons, not through every choice.
+	for {
+		ev, ok := gtx.Event(key.Filter{Name: key.NameTab, Optional: key.ModShift})
+		if !ok {
+			break
+		}
+		if e, ok := ev.(key.Event); ok && e.State == key.Press {
+			d.countdown.pause()
+			d.cycleFocus(gtx, e.Modifiers.Contain(key.ModShift))
+		}
+	}
+	return submit
+}
+
+// applyFilter shows the choices that match query and selects the best
+// match.
+func (d *selectDialog) applyFilter(query string) {
+	switch {
+	case query == "":
+		d.filtered = d.all
+	case d.query != "" && strings.HasPrefix(query, d.query):
+		// A longer query only matches a subset of the current matches.
+		d.filtered = d.matcher.filter(query, d.filtered)
+	default:
+		d.filtered = d.matcher.filter(query, d.all)
+	}
+	d.query = query
+
+	d.list.Position = layout.Position{}
+	switch {
+	case query == "" && d.selectedIndex >= 0:
+		// Without a query, positions are choice indexes.
+		d.selectPosition(d.selectedIndex)
+	case len(d.filtered) > 0:
+		d.selectPosition(0)
+	default:
+		d.selectedIndex = -1
+	}
+}
+
+// navigate moves the selection according to the navigation key name.
+func (d *selectDialog) navigate(name key.Name) {
+	n := len(d.filtered)
+	if n == 0 {
+		return
+	}
+	// A page is the number of completely visible items.
+	page := max(d.list.Position.Count-1, 1)
+	i := slices.Index(d.filtered, d.selectedIndex)
+	switch name {
+	case key.NameUpArrow:
+		i--
+	case key.NameDownArrow:
+		i++
+	case key.NameHome:
+		i = 0
+	case key.NameEnd:
+		i = n - 1
+	case key.NamePageUp:
+		i -= page
+	case key.NamePageDown:
+		i += page
+	}
+	d.selectPosition(min(max(i, 0), n-1))
 }
 
-func (d *selectDialog) layout(gtx layout.Context, th *material.Theme) layout.Dimensions {
+// typeAhead selects the first choice that starts with the letters typed
+// since the last pause.
+func (d *selectDialog) typeAhead(now time.Time, text string) {
+	if now.Sub(d.typedAt) > typeAheadTimeout {
+		d.typed = ""
+	}
+	d.typed += strings.ToLower(text)
+	d.typedAt = now
+	for i, choice := range d.filtered {
+		if strings.HasPrefix(strings.ToLower(d.Choices[choice]), d.typed) {
+			d.selectPosition(i)
+			return
+		}
+	}
+}
+
+// selectPosition selects the choice at position i of the list and scrolls
+// the list to keep it visible.
+func (d *selectDialog) selectPosition(i int) {
+	d.selectedIndex = d.filtered[i]
+	pos := &d.list.Position
+	switch {
+	case i < pos.First || i == pos.First && pos.Offset > 0:
+		pos.First, pos.Offset = i, 0
+	case pos.Count > 0 && i >= pos.First+pos.Count-1:
+		// The last visible item may be cut off, so scroll until i is the
+		// last completely visible one.
+		pos.First, pos.Offset = max(i-pos.Count+2, 0), 0
+	}
+}
+
+// cycleFocus moves the focus to the next, or previous, of the search
+// field, the list, the custom entry and the buttons.
+func (d *selectDialog) cycleFocus(gtx layout.Context, backward bool) {
+	var order []event.Tag
+	if d.Searchable {
+		order = append(order, &d.search)
+	}
+	order = append(order, d)
+	if d.AllowCustomEntry {
+		order = append(order, &d.customInput)
+	}
+	for i := range d.buttonClicks {
+		order = append(order, &d.buttonClicks[i])
+	}
+
+	current := -1
+	for i, tag := range order {
+		if gtx.Focused(tag) {
+			current = i
+			break
+		}
+	}
+	next := current + 1
+	if backward {
+		next = current - 1 + len(order)
+	}
+	gtx.Execute(key.FocusCmd{Tag: order[next%len(order)]})
+}
+
+func (d *selectDialog) layoutBody(gtx layout.Context, th *material.Theme) layout.Dimensions {
 	materialList := material.List(th, &d.list)
 	materialList.AnchorStrategy = material.Occupy
-	return layout.UniformInset(unit.Dp(20)).Layout(gtx, func(gtx layout.Context) layout.Dimensions {
-		return layout.Flex{Axis: layout.Vertical, Spacing: layout.SpaceAround}.Layout(gtx,
-			// Label
-			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
-				label := material.H6(th, d.Label)
-				return label.Layout(gtx)
-			}),
-			// Description
-			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
-				if d.Description == "" {
-					return layout.Dimensions{}
+	return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+		// Search field if enabled
+		layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+			if !d.Searchable {
+				return layout.Dimensions{}
+			}
+			return styledEditor(gtx, th, &d.search, "Search...")
+		}),
+		// Choices with scrollable list
+		layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+			// Set height to show approximately 3 items (button height ~40dp + spacing)
+			maxHeight := unit.Dp(140)
+			gtx.Constraints.Max.Y = gtx.Dp(maxHeight)
+
+			// Outline the list while it has the keyboard focus
+			border := widget.Border{Width: unit.Dp(1), CornerRadius: unit.Dp(4)}
+			if gtx.Focused(d) {
+				border.Color = th.Palette.ContrastBg
+			}
+			dims := border.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+				if len(d.filtered) == 0 && len(d.Choices) > 0 {
+					return layout.UniformInset(unit.Dp(8)).Layout(gtx, material.Caption(th, "No matches").Layout)
 				}
-				desc := material.Body1(th, d.Description)
-				desc.Color = th.Fg
-				return desc.Layout(gtx)
-			}),
-			// Choices with scrollable list
-			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
-				// Set height to show approximately 3 items (button height ~40dp + spacing)
-				maxHeight := unit.Dp(140)
-				gtx.Constraints.Max.Y = gtx.Dp(maxHeight)
-
-				return materialList.Layout(gtx, len(d.Choices), func(gtx layout.Context, i int) layout.Dimensions {
-					return d.choiceItem(gtx, th, i)
+				return materialList.Layout(gtx, len(d.filtered), func(gtx layout.Context, i int) layout.Dimensions {
+					return d.choiceItem(gtx, th, d.filtered[i])
 				})
-			}),
-			// Custom entry if allowed
-			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
-				if !d.AllowCustomEntry {
-					return layout.Dimensions{}
-				}
-				return layout.Flex{Axis: layout.Horizontal}.Layout(gtx,
-					layout.Rigid(func(gtx layout.Context) layout.Dimensions {
-						label := material.Body1(th, "Other: ")
-						return label.Layout(gtx)
-					}),
-					layout.Flexed(1, func(gtx layout.Context) layout.Dimensions {
-						return d.styledEditor(gtx, th)
-					}),
-				)
-			}),
-			// Buttons
-			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
-				return layout.Flex{Axis: layout.Horizontal, Spacing: layout.SpaceEnd}.Layout(gtx,
-					layout.Rigid(func(gtx layout.Context) layout.Dimensions {
-						btn := material.Button(th, &d.cancelButton, "Cancel")
-						return btn.Layout(gtx)
-					}),
-					layout.Rigid(func(gtx layout.Context) layout.Dimensions {
-						return layout.Spacer{Width: unit.Dp(10)}.Layout(gtx)
-					}),
-					layout.Rigid(func(gtx layout.Context) layout.Dimensions {
-						btn := material.Button(th, &d.okButton, "OK")
-						return btn.Layout(gtx)
-					}),
-				)
-			}),
-		)
-	})
+			})
+			defer clip.Rect{Max: dims.Size}.Push(gtx.Ops).Pop()
+			event.Op(gtx.Ops, d)
+			return dims
+		}),
+		// Custom entry if allowed
+		layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+			if !d.AllowCustomEntry {
+				return layout.Dimensions{}
+			}
+			return layout.Flex{Axis: layout.Horizontal}.Layout(gtx,
+				layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+					label := material.Body1(th, "Other: ")
+					return label.Layout(gtx)
+				}),
+				layout.Flexed(1, func(gtx layout.Context) layout.Dimensions {
+					return styledEditor(gtx, th, &d.customInput, "")
+				}),
+			)
+		}),
+	)
 }
 
 func (d *selectDialog) choiceItem(gtx layout.Context, th *material.Theme, i int) layout.Dimensions {
@@ -277,37 +377,88 @@ func (d *selectDialog) choiceItem(gtx layout.Context, th *material.Theme, i int)
 
 	// Check if this item was clicked
 	if d.choiceButtons[i].Clicked(gtx) {
+		d.countdown.pause()
 		d.selectedIndex = i
+		// Keep the focus on the list for keyboard navigation
+		gtx.Execute(key.FocusCmd{Tag: d})
 	}
 
 	// Create button style with enhanced selection indicator
-	var buttonText string
-	btn := material.Button(th, &d.choiceButtons[i], "")
+	btn := material.ButtonLayout(th, &d.choiceButtons[i])
+	var prefix string
+	var fg, highlight color.NRGBA
 
 	if d.selectedIndex == i {
 		// Selected item: use high contrast colors and add checkmark
 		btn.Background = th.Palette.ContrastBg
-		btn.Color = th.Palette.ContrastFg
-		buttonText = "✓ " + choice
+		fg, highlight = th.Palette.ContrastFg, th.Palette.ContrastFg
+		prefix = "✓ "
 	} else {
 		// Unselected item: use subtle styling
 		btn.Background = th.Bg
-		btn.Color = th.Fg
-		buttonText = "  " + choice
+		fg, highlight = th.Fg, th.Palette.ContrastBg
+		prefix = "  "
 	}
 
-	btn.Text = buttonText
-	return btn.Layout(gtx)
+	// Highlight the characters that match the search query
+	var matched []int
+	if d.query != "" {
+		matched = d.matcher.positions(d.query, i)
+	}
+
+	return btn.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+		return layout.Inset{
+			Top: 10, Bottom: 10,
+			Left: 12, Right: 12,
+		}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+			return layout.Center.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+				return highlightedLabel(gtx, th, prefix, choice, matched, fg, highlight)
+			})
+		})
+	})
 }
 
-func (d *selectDialog) handleOK() {
+// highlightedLabel lays out prefix and text in a single line, with the
+// runes of text at the matched positions in bold and the highlight color.
+func highlightedLabel(gtx layout.Context, th *material.Theme, prefix, text string, matched []int, fg, highlight color.NRGBA) layout.Dimensions {
+	segment := func(text string, match bool) layout.FlexChild {
+		return layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+			l := material.Label(th, th.TextSize*14.0/16.0, text)
+			l.MaxLines = 1
+			l.Color = fg
+			if match {
+				l.Color = highlight
+				l.Font.Weight = font.Bold
+			}
+			return l.Layout(gtx)
+		})
+	}
+
+	children := []layout.FlexChild{segment(prefix, false)}
+	runes := []rune(text)
+	match := make([]bool, len(runes))
+	for _, p := range matched {
+		match[p] = true
+	}
+	// Adjacent runes that are both matched, or both not, share a label.
+	for start := 0; start < len(runes); {
+		end := start + 1
+		for end < len(runes) && match[end] == match[start] {
+			end++
+		}
+		children = append(children, segment(string(runes[start:end]), match[start]))
+		start = end
+	}
+	return layout.Flex{Axis: layout.Horizontal, Alignment: layout.Baseline}.Layout(gtx, children...)
+}
+
+func (d *selectDialog) confirm() bool {
 	// Check if custom entry is provided and not empty
 	if d.AllowCustomEntry {
 		customText := d.customInput.Text()
 		if customText != "" {
 			d.selected = customText
-			d.canceled = false
-			return
+			return true
 		}
 	}
 
@@ -317,10 +468,7 @@ func (d *selectDialog) handleOK() {
 	} else {
 		d.selected = ""
 	}
-	d.canceled = false
+	return true
 }
 
-func (d *selectDialog) handleCancel() {
-	d.selected = ""
-	d.canceled = true
-}
+func (d *selectDialog) result() Result { return Result{Outcome: d.outcome, Value: d.selected} }
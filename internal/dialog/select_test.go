@@ -0,0 +1,167 @@
+package dialog
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"slices"
+	"testing"
+
+	"gioui.org/io/event"
+	"gioui.org/io/key"
+)
+
+func TestSelectNavigation(t *testing.T) {
+	choices := make([]string, 20)
+	for i := range choices {
+		choices[i] = fmt.Sprintf("item %02d", i)
+	}
+
+	tests := []struct {
+		name string
+		keys []key.Name
+		want int
+	}{
+		{name: "down", keys: []key.Name{key.NameDownArrow, key.NameDownArrow}, want: 7},
+		{name: "up", keys: []key.Name{key.NameUpArrow}, want: 4},
+		{name: "up at top", keys: []key.Name{key.NameHome, key.NameUpArrow}, want: 0},
+		{name: "end", keys: []key.Name{key.NameEnd}, want: 19},
+		{name: "down at bottom", keys: []key.Name{key.NameEnd, key.NameDownArrow}, want: 19},
+		{name: "page down", keys: []key.Name{key.NamePageDown}, want: 5 + pageSize(t, choices)},
+		{name: "page up", keys: []key.Name{key.NamePageUp}, want: max(5-pageSize(t, choices), 0)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := NewSelectDialog(0, 0, "", "", "", choices, "item 05", false)
+			h := newHarness(t, d)
+			for _, k := range tt.keys {
+				if h.press(k) {
+					t.Fatalf("%s closed the dialog", k)
+				}
+			}
+			if d.selectedIndex != tt.want {
+				t.Errorf("got selection %d, want %d", d.selectedIndex, tt.want)
+			}
+			first, count := d.list.Position.First, d.list.Position.Count
+			if d.selectedIndex < first || d.selectedIndex >= first+count {
+				t.Errorf("selection %d not visible in items %d to %d", d.selectedIndex, first, first+count-1)
+			}
+		})
+	}
+}
+
+// pageSize returns the number of completely visible items of a select
+// dialog with the given choices.
+func pageSize(t *testing.T, choices []string) int {
+	d := NewSelectDialog(0, 0, "", "", "", choices, "", false)
+	newHarness(t, d)
+	return d.list.Position.Count - 1
+}
+
+func TestSelectTypeAhead(t *testing.T) {
+	d := NewSelectDialog(0, 0, "", "", "", []string{"Apple", "Banana", "Blueberry", "Cherry"}, "", false)
+	h := newHarness(t, d)
+
+	h.typeText("b")
+	if d.selectedIndex != 1 {
+		t.Errorf("got selection %d after \"b\", want 1", d.selectedIndex)
+	}
+	h.typeText("l")
+	if d.selectedIndex != 2 {
+		t.Errorf("got selection %d after \"bl\", want 2", d.selectedIndex)
+	}
+
+	// After a pause, typing starts a new search.
+	h.now = h.now.Add(2 * typeAheadTimeout)
+	h.typeText("c")
+	if d.selectedIndex != 3 {
+		t.Errorf("got selection %d after pause and \"c\", want 3", d.selectedIndex)
+	}
+
+	if !h.press(key.NameReturn) {
+		t.Fatal("Enter didn't confirm the dialog")
+	}
+	if want := (Result{Outcome: Confirmed, Value: "Cherry"}); !reflect.DeepEqual(d.result(), want) {
+		t.Errorf("got %+v, want %+v", d.result(), want)
+	}
+}
+
+func TestSelectTabCycle(t *testing.T) {
+	d := NewSelectDialog(0, 0, "", "", "", []string{"a", "b"}, "a", true)
+	h := newHarness(t, d)
+
+	focused := func() event.Tag {
+		for _, tag := range []event.Tag{d, &d.customInput, &d.buttonClicks[0], &d.buttonClicks[1]} {
+			if h.router.Source().Focused(tag) {
+				return tag
+			}
+		}
+		return nil
+	}
+	if focused() != event.Tag(d) {
+		t.Fatal("list not focused initially")
+	}
+
+	forward := []event.Tag{&d.customInput, &d.buttonClicks[0], &d.buttonClicks[1], d}
+	for i, want := range forward {
+		h.press(key.NameTab)
+		if got := focused(); got != want {
+			t.Errorf("Tab %d: focused %T, want %T", i+1, got, want)
+		}
+	}
+
+	h.router.Queue(key.Event{Name: key.NameTab, Modifiers: key.ModShift, State: key.Press})
+	h.frame()
+	h.frame()
+	if got := focused(); got != event.Tag(&d.buttonClicks[1]) {
+		t.Errorf("Shift+Tab: focused %T, want the OK button", got)
+	}
+}
+
+func TestSelectSearch(t *testing.T) {
+	choices := []string{"main", "feature/login", "fix/menu", "release/1.0"}
+	d := NewSelectDialog(0, 0, "", "", "", choices, "release/1.0", false)
+	d.Searchable = true
+	d.SearchMode = MatchFuzzy
+	h := newHarness(t, d)
+
+	h.typeText("f")
+	if want := []int{1, 2}; !slices.Equal(d.filtered, want) {
+		t.Errorf("got %v after \"f\", want %v", d.filtered, want)
+	}
+	h.typeText("m")
+	if want := []int{2}; !slices.Equal(d.filtered, want) {
+		t.Errorf("got %v after \"fm\", want %v", d.filtered, want)
+	}
+	if d.selectedIndex != 2 {
+		t.Errorf("got selection %d, want the best match 2", d.selectedIndex)
+	}
+
+	// Clearing the query restores all choices.
+	h.router.Queue(key.EditEvent{Range: key.Range{End: math.MaxInt32}})
+	h.frame()
+	if !slices.Equal(d.filtered, d.all) {
+		t.Errorf("got %v after clearing the query, want all choices", d.filtered)
+	}
+
+	// Enter doesn't confirm the dialog while nothing matches.
+	h.typeText("xyz")
+	if len(d.filtered) != 0 || d.selectedIndex != -1 {
+		t.Fatalf("got %v, selection %d for a query without matches", d.filtered, d.selectedIndex)
+	}
+	if h.press(key.NameReturn) {
+		t.Fatalf("Enter without a match closed the dialog with %+v", d.result())
+	}
+	h.router.Queue(key.EditEvent{Range: key.Range{End: math.MaxInt32}})
+	h.frame()
+
+	// The list is navigated while the search field has the focus.
+	h.typeText("l")
+	h.press(key.NameDownArrow)
+	if !h.press(key.NameReturn) {
+		t.Fatal("Enter in the search field didn't confirm the dialog")
+	}
+	if want := (Result{Outcome: Confirmed, Value: "release/1.0"}); !reflect.DeepEqual(d.result(), want) {
+		t.Errorf("got %+v, want %+v", d.result(), want)
+	}
+}
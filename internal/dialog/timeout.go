@@ -0,0 +1,98 @@
+package dialog
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"gioui.org/io/event"
+	"gioui.org/io/pointer"
+	"gioui.org/layout"
+	"gioui.org/op"
+)
+
+// TimeoutAction defines how a dialog is closed when its timeout expires.
+type TimeoutAction int
+
+const (
+	// TimeoutCancel cancels the dialog.
+	TimeoutCancel TimeoutAction = iota
+	// TimeoutConfirm confirms the dialog with its default value.
+	TimeoutConfirm
+)
+
+// countdown closes a dialog after a timeout. It is paused for good as soon
+// as the user interacts with the dialog.
+type countdown struct {
+	timeout time.Duration
+
+	deadline time.Time
+	paused   bool
+}
+
+// active reports whether the countdown is still running.
+func (c *countdown) active() bool {
+	return c.timeout > 0 && !c.paused
+}
+
+// pause stops the countdown, e.g. because the user started interacting.
+func (c *countdown) pause() {
+	c.paused = true
+}
+
+// watch pauses the countdown when the user presses a pointer button inside
+// the current clip area.
+func (c *countdown) watch(gtx layout.Context) {
+	if !c.active() {
+		return
+	}
+	for {
+		ev, ok := gtx.Event(pointer.Filter{Target: c, Kinds: pointer.Press})
+		if !ok {
+			break
+		}
+		if _, ok := ev.(pointer.Event); ok {
+			c.pause()
+		}
+	}
+	event.Op(gtx.Ops, c)
+}
+
+// expired reports whether the timeout has passed. While the countdown is
+// running, it requests a frame for every second, so that labels showing
+// the remaining time stay up to date.
+func (c *countdown) expired(gtx layout.Context) bool {
+	if !c.active() {
+		return false
+	}
+	if c.deadline.IsZero() {
+		c.deadline = gtx.Now.Add(c.timeout)
+	}
+	left := c.deadline.Sub(gtx.Now)
+	if left <= 0 {
+		return true
+	}
+	next := left % time.Second
+	if next == 0 {
+		next = time.Second
+	}
+	gtx.Execute(op.InvalidateCmd{At: gtx.Now.Add(next)})
+	return false
+}
+
+// remaining returns the number of seconds left, rounded up.
+func (c *countdown) remaining(now time.Time) int {
+	if c.deadline.IsZero() {
+		return int(math.Ceil(c.timeout.Seconds()))
+	}
+	return int(math.Ceil(c.deadline.Sub(now).Seconds()))
+}
+
+// label appends the remaining seconds to the text of the button that is
+// triggered by the timeout, e.g. "OK (12)".
+func (c *countdown) label(gtx layout.Context, text string) string {
+	if !c.active() {
+		return text
+	}
+	return fmt.Sprintf("%s (%d)", text, max(c.remaining(gtx.Now), 0))
+}
@@ -0,0 +1,118 @@
+package dialog
+
+import (
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"gioui.org/f32"
+	"gioui.org/io/pointer"
+	"gioui.org/layout"
+)
+
+func TestTimeout(t *testing.T) {
+	tests := []struct {
+		name   string
+		dialog func() Dialog
+		action TimeoutAction
+		want   string
+	}{
+		{"base/cancel", func() Dialog { return NewBaseDialog(0, 0, "", "", "") }, TimeoutCancel, ""},
+		{"input/cancel", func() Dialog { return NewInputDialog(0, 0, "", "", "", "gio", nil) }, TimeoutCancel, ""},
+		{"input/confirm", func() Dialog { return NewInputDialog(0, 0, "", "", "", "gio", nil) }, TimeoutConfirm, "gio"},
+		{"save file/confirm", func() Dialog {
+			return NewSaveFileDialog(0, 0, "", "", "", fstest.MapFS{}, ".", "notes.txt", nil)
+		}, TimeoutConfirm, "notes.txt"},
+		{"form/cancel", func() Dialog {
+			return NewFormDialog(0, 0, "", "", "", []FormField{{Name: "name", Default: "gio"}})
+		}, TimeoutCancel, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := tt.dialog()
+			b := d.base()
+			b.Timeout, b.TimeoutAction = time.Second, tt.action
+			h := newHarness(t, d)
+
+			// The default text doesn't count as an edit that pauses the
+			// countdown.
+			if !b.countdown.active() {
+				t.Fatal("countdown paused without user input")
+			}
+			h.now = h.now.Add(time.Second - 100*time.Millisecond)
+			if h.frame() {
+				t.Fatal("dialog closed before the timeout")
+			}
+			h.now = h.now.Add(100 * time.Millisecond)
+			if !h.frame() {
+				t.Fatal("dialog still open after the timeout")
+			}
+			if res := d.result(); res.Outcome != TimedOut || res.Value != tt.want {
+				t.Errorf("got %+v, want outcome %v and value %q", res, TimedOut, tt.want)
+			}
+		})
+	}
+}
+
+func TestTimeoutPause(t *testing.T) {
+	tests := []struct {
+		name     string
+		interact func(h *harness)
+	}{
+		{"pointer press", func(h *harness) {
+			h.router.Queue(
+				pointer.Event{Kind: pointer.Press, Source: pointer.Mouse, Buttons: pointer.ButtonPrimary, Position: f32.Pt(10, 10)},
+				pointer.Event{Kind: pointer.Release, Source: pointer.Mouse, Position: f32.Pt(10, 10)},
+			)
+			h.frame()
+		}},
+		{"edit", func(h *harness) { h.typeText("!") }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := NewInputDialog(0, 0, "", "", "", "gio", nil)
+			d.Timeout, d.TimeoutAction = time.Second, TimeoutConfirm
+			h := newHarness(t, d)
+
+			tt.interact(h)
+			if d.countdown.active() {
+				t.Fatal("countdown still running")
+			}
+			h.now = h.now.Add(time.Minute)
+			if h.frame() {
+				t.Fatalf("paused dialog closed with %+v", d.result())
+			}
+		})
+	}
+}
+
+func TestTimeoutLabel(t *testing.T) {
+	tests := []struct {
+		action TimeoutAction
+		button string
+	}{
+		{TimeoutCancel, "Cancel"},
+		{TimeoutConfirm, "OK"},
+	}
+	for _, tt := range tests {
+		d := NewBaseDialog(0, 0, "", "", "")
+		d.Timeout, d.TimeoutAction = 3*time.Second, tt.action
+		h := newHarness(t, d)
+		gtx := layout.Context{Now: h.now}
+
+		if got := d.buttons[d.timeoutButton()].Label; got != tt.button {
+			t.Errorf("action %v counts down on %q, want %q", tt.action, got, tt.button)
+		}
+		if got, want := d.countdown.label(gtx, "OK"), "OK (3)"; got != want {
+			t.Errorf("label = %q, want %q", got, want)
+		}
+		gtx.Now = gtx.Now.Add(1500 * time.Millisecond)
+		if got, want := d.countdown.label(gtx, "OK"), "OK (2)"; got != want {
+			t.Errorf("label after 1.5s = %q, want %q", got, want)
+		}
+		d.countdown.pause()
+		if got := d.countdown.label(gtx, "OK"); got != "OK" {
+			t.Errorf("label of a paused countdown = %q, want %q", got, "OK")
+		}
+	}
+}
@@ -0,0 +1,162 @@
+package dialog
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"gioui.org/layout"
+	"gioui.org/op"
+)
+
+// defaultValidateDelay is the debounce delay used when live validation is
+// configured without an explicit delay.
+const defaultValidateDelay = 300 * time.Millisecond
+
+// liveValidator runs debounced, optionally asynchronous validation of editor
+// content without blocking the frame loop.
+//
+// Every edit reschedules the validation, so the validators only run after the
+// user stopped typing for the configured delay. A running asynchronous
+// validation is canceled as soon as the content changes again.
+type liveValidator struct {
+	validate      func(string) error
+	validateAsync func(context.Context, string) error
+	delay         time.Duration
+
+	// invalidate requests a new frame once an asynchronous validation
+	// finished. It is called from the validating goroutine.
+	invalidate func()
+
+	mu       sync.Mutex
+	text     string
+	deadline time.Time
+	running  bool
+	cancel   context.CancelFunc
+	seq      int
+	err      error
+}
+
+// enabled reports whether any live validator is configured.
+func (v *liveValidator) enabled() bool {
+	return v.validate != nil || v.validateAsync != nil
+}
+
+// schedule (re-)schedules the validation of text after the debounce delay,
+// counting from now. Pending results for previous content are discarded.
+func (v *liveValidator) schedule(text string, now time.Time) {
+	if !v.enabled() {
+		return
+	}
+	delay := v.delay
+	if delay <= 0 {
+		delay = defaultValidateDelay
+	}
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.stopLocked()
+	v.text = text
+	v.deadline = now.Add(delay)
+	v.err = nil
+}
+
+// update runs a scheduled validation once its deadline has passed, or
+// requests a frame for the moment it will be due.
+func (v *liveValidator) update(gtx layout.Context) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if v.deadline.IsZero() {
+		return
+	}
+	if gtx.Now.Before(v.deadline) {
+		gtx.Execute(op.InvalidateCmd{At: v.deadline})
+		return
+	}
+	v.deadline = time.Time{}
+	v.runLocked()
+}
+
+// flush runs a scheduled validation immediately instead of waiting for the
+// debounce delay, e.g. when the user confirms the dialog.
+func (v *liveValidator) flush() {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if v.deadline.IsZero() {
+		return
+	}
+	v.deadline = time.Time{}
+	v.runLocked()
+}
+
+func (v *liveValidator) runLocked() {
+	text := v.text
+	if v.validate != nil {
+		if v.err = v.validate(text); v.err != nil {
+			return
+		}
+	}
+	if v.validateAsync == nil {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	v.cancel = cancel
+	v.running = true
+	v.seq++
+	seq := v.seq
+	go func() {
+		err := v.validateAsync(ctx, text)
+		cancel()
+
+		v.mu.Lock()
+		// Results for outdated content are dropped.
+		if seq == v.seq {
+			v.running = false
+			v.err = err
+		}
+		invalidate := v.invalidate
+		v.mu.Unlock()
+
+		if invalidate != nil {
+			invalidate()
+		}
+	}()
+}
+
+// busy reports whether a validation is scheduled or still running.
+func (v *liveValidator) busy() bool {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return !v.deadline.IsZero() || v.running
+}
+
+// checking reports whether an asynchronous validation is running.
+func (v *liveValidator) checking() bool {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return v.running
+}
+
+// result returns the outcome of the most recent completed validation.
+func (v *liveValidator) result() error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return v.err
+}
+
+// stop cancels any scheduled or running validation.
+func (v *liveValidator) stop() {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.stopLocked()
+	v.deadline = time.Time{}
+}
+
+func (v *liveValidator) stopLocked() {
+	if v.cancel != nil {
+		v.cancel()
+		v.cancel = nil
+	}
+	v.running = false
+	v.seq++
+}
@@ -0,0 +1,257 @@
+package dialog
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"gioui.org/io/key"
+)
+
+const testValidateDelay = 100 * time.Millisecond
+
+// asyncCall is a call of the asynchronous validator of asyncValidator.
+type asyncCall struct {
+	ctx    context.Context
+	text   string
+	result chan error
+}
+
+// asyncValidator returns an asynchronous validator that hands every call
+// to the test and waits for the test to send its result.
+func asyncValidator() (validate func(context.Context, string) error, calls chan asyncCall) {
+	calls = make(chan asyncCall, 4)
+	validate = func(ctx context.Context, text string) error {
+		c := asyncCall{ctx: ctx, text: text, result: make(chan error)}
+		calls <- c
+		return <-c.result
+	}
+	return validate, calls
+}
+
+// newLiveHarness opens d and returns a channel that receives a value
+// whenever an asynchronous validation finished.
+func newLiveHarness(t *testing.T, d *inputDialog) (*harness, chan struct{}) {
+	t.Helper()
+	h := newHarness(t, d)
+	done := make(chan struct{}, 4)
+	d.live.mu.Lock()
+	d.live.invalidate = func() { done <- struct{}{} }
+	d.live.mu.Unlock()
+	return h, done
+}
+
+// wait advances the clock of h past the debounce delay.
+func (h *harness) wait() {
+	h.t.Helper()
+	h.now = h.now.Add(testValidateDelay)
+	h.frame()
+}
+
+func nextCall(t *testing.T, calls chan asyncCall) asyncCall {
+	t.Helper()
+	select {
+	case c := <-calls:
+		return c
+	case <-time.After(5 * time.Second):
+		t.Fatal("the asynchronous validator wasn't called")
+		return asyncCall{}
+	}
+}
+
+func waitDone(t *testing.T, h *harness, done chan struct{}) {
+	t.Helper()
+	select {
+	case <-done:
+		h.frame()
+	case <-time.After(5 * time.Second):
+		t.Fatal("the asynchronous validation didn't finish")
+	}
+}
+
+func TestLiveValidationDebounce(t *testing.T) {
+	errShort := errors.New("too short")
+	var validated []string
+	d := NewInputDialog(0, 0, "", "", "", "", nil)
+	d.ValidateDelay = testValidateDelay
+	d.ValidateLive = func(s string) error {
+		validated = append(validated, s)
+		if len(s) < 3 {
+			return errShort
+		}
+		return nil
+	}
+	h := newHarness(t, d)
+
+	if !d.canConfirm() || len(validated) > 0 {
+		t.Fatalf("input validated before it was changed: %q", validated)
+	}
+	h.typeText("a")
+	h.typeText("b")
+	if len(validated) > 0 {
+		t.Errorf("validated while typing: %q", validated)
+	}
+	if d.canConfirm() {
+		t.Error("OK enabled while the validation is pending")
+	}
+
+	h.wait()
+	if len(validated) != 1 || validated[0] != "ab" {
+		t.Errorf("validated %q, want only the final text", validated)
+	}
+	if err := d.live.result(); err != errShort || d.canConfirm() {
+		t.Errorf("error = %v, OK enabled = %t, want %v and disabled", err, d.canConfirm(), errShort)
+	}
+	if h.press(key.NameReturn) {
+		t.Fatal("Enter confirmed invalid input")
+	}
+
+	h.typeText("c")
+	if d.live.result() != nil {
+		t.Error("the error of the previous text is still shown")
+	}
+	h.wait()
+	if err := d.live.result(); err != nil || !d.canConfirm() {
+		t.Errorf("error = %v, OK enabled = %t, want no error and enabled", err, d.canConfirm())
+	}
+}
+
+func TestLiveValidationAsync(t *testing.T) {
+	errTaken := errors.New("already taken")
+	validate, calls := asyncValidator()
+	d := NewInputDialog(0, 0, "", "", "", "", nil)
+	d.ValidateDelay = testValidateDelay
+	d.ValidateAsync = validate
+	h, done := newLiveHarness(t, d)
+
+	h.typeText("gio")
+	h.wait()
+	c := nextCall(t, calls)
+	if c.text != "gio" {
+		t.Errorf("validating %q, want %q", c.text, "gio")
+	}
+	if !d.live.checking() || d.canConfirm() {
+		t.Errorf("checking = %t, OK enabled = %t while validating", d.live.checking(), d.canConfirm())
+	}
+
+	c.result <- errTaken
+	waitDone(t, h, done)
+	if err := d.live.result(); err != errTaken || d.live.checking() || d.canConfirm() {
+		t.Errorf("error = %v, OK enabled = %t, want %v and disabled", err, d.canConfirm(), errTaken)
+	}
+
+	h.typeText("ui")
+	h.wait()
+	nextCall(t, calls).result <- nil
+	waitDone(t, h, done)
+	if err := d.live.result(); err != nil || !d.canConfirm() {
+		t.Errorf("error = %v, OK enabled = %t, want no error and enabled", err, d.canConfirm())
+	}
+}
+
+func TestLiveValidationStaleResult(t *testing.T) {
+	validate, calls := asyncValidator()
+	d := NewInputDialog(0, 0, "", "", "", "", nil)
+	d.ValidateDelay = testValidateDelay
+	d.ValidateAsync = validate
+	h, done := newLiveHarness(t, d)
+
+	h.typeText("a")
+	h.wait()
+	stale := nextCall(t, calls)
+
+	// Changing the text cancels the running validation.
+	h.typeText("b")
+	if stale.ctx.Err() == nil {
+		t.Error("the validation of the previous text wasn't canceled")
+	}
+	// A validator that ignores the cancellation has its result dropped.
+	stale.result <- errors.New("stale")
+	waitDone(t, h, done)
+	if err := d.live.result(); err != nil {
+		t.Errorf("stale error %v shown", err)
+	}
+	if d.canConfirm() {
+		t.Error("OK enabled before the current text was validated")
+	}
+
+	h.wait()
+	c := nextCall(t, calls)
+	if c.text != "ab" {
+		t.Errorf("validating %q, want %q", c.text, "ab")
+	}
+	c.result <- nil
+	waitDone(t, h, done)
+	if !d.canConfirm() {
+		t.Error("OK disabled after the current text was validated")
+	}
+}
+
+func TestLiveValidationStop(t *testing.T) {
+	validate, calls := asyncValidator()
+	d := NewInputDialog(0, 0, "", "", "", "", nil)
+	d.ValidateDelay = testValidateDelay
+	d.ValidateAsync = validate
+	h, _ := newLiveHarness(t, d)
+
+	h.typeText("a")
+	h.wait()
+	c := nextCall(t, calls)
+	d.close()
+	if c.ctx.Err() == nil {
+		t.Error("closing the dialog didn't cancel the validation")
+	}
+	c.result <- nil
+}
+
+func TestLiveValidationFlush(t *testing.T) {
+	errBad := errors.New("bad")
+	d := NewInputDialog(0, 0, "", "", "", "", nil)
+	d.ValidateDelay = testValidateDelay
+	d.ValidateLive = func(s string) error {
+		if s == "bad" {
+			return errBad
+		}
+		return nil
+	}
+	h := newHarness(t, d)
+
+	// Confirming doesn't wait for the debounce delay.
+	h.typeText("bad")
+	if h.press(key.NameReturn) {
+		t.Fatal("Enter confirmed invalid input")
+	}
+	if err := d.live.result(); err != errBad {
+		t.Errorf("error = %v, want %v", err, errBad)
+	}
+	h.typeText("!")
+	if !h.press(key.NameReturn) {
+		t.Fatal("Enter didn't confirm valid input")
+	}
+	if res := d.result(); res.Value != "bad!" {
+		t.Errorf("got %+v, want the confirmed input", res)
+	}
+}
+
+func TestLiveValidationFlushAsync(t *testing.T) {
+	validate, calls := asyncValidator()
+	d := NewInputDialog(0, 0, "", "", "", "gio", nil)
+	d.ValidateDelay = testValidateDelay
+	d.ValidateAsync = validate
+	h, done := newLiveHarness(t, d)
+
+	// The unchanged default text is validated when the user confirms it,
+	// and the dialog stays open until the result is known.
+	if h.press(key.NameReturn) {
+		t.Fatal("Enter confirmed input that wasn't validated")
+	}
+	nextCall(t, calls).result <- nil
+	waitDone(t, h, done)
+	if !h.press(key.NameReturn) {
+		t.Fatal("Enter didn't confirm valid input")
+	}
+	if res := d.result(); res.Value != "gio" {
+		t.Errorf("got %+v, want the default text", res)
+	}
+}
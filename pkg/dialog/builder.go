@@ -0,0 +1,1114 @@
+package dialog
+
+import (
+	"context"
+	"io/fs"
+	"slices"
+	"time"
+)
+
+// InputDialogBuilder configures a text-input dialog step by step.
+//
+// Every setter modifies the builder and returns it, so that calls can be
+// chained. Use Clone to derive several dialogs from a partially configured
+// template without modifying it.
+type InputDialogBuilder struct {
+	opts InputDialogOptions
+}
+
+// NewInputDialog starts the configuration of a text-input dialog.
+func NewInputDialog() *InputDialogBuilder {
+	return &InputDialogBuilder{}
+}
+
+// Size sets the dimensions of the dialog window.
+func (b *InputDialogBuilder) Size(width, height float32) *InputDialogBuilder {
+	b.opts.Width, b.opts.Height = width, height
+	return b
+}
+
+// Title sets the window title.
+func (b *InputDialogBuilder) Title(title string) *InputDialogBuilder {
+	b.opts.Title = title
+	return b
+}
+
+// Label sets the prompt label.
+func (b *InputDialogBuilder) Label(label string) *InputDialogBuilder {
+	b.opts.Label = label
+	return b
+}
+
+// Description sets the additional description or help text.
+func (b *InputDialogBuilder) Description(description string) *InputDialogBuilder {
+	b.opts.Description = description
+	return b
+}
+
+// Default sets the initial text shown in the input field.
+func (b *InputDialogBuilder) Default(text string) *InputDialogBuilder {
+	b.opts.DefaultText = text
+	return b
+}
+
+// Validate sets the validation function; see InputDialogOptions.Validate.
+func (b *InputDialogBuilder) Validate(validate func(string) error) *InputDialogBuilder {
+	b.opts.Validate = validate
+	return b
+}
+
+// ValidateLive sets the live validation function; see InputDialogOptions.ValidateLive.
+func (b *InputDialogBuilder) ValidateLive(validate func(string) error) *InputDialogBuilder {
+	b.opts.ValidateLive = validate
+	return b
+}
+
+// ValidateAsync sets the asynchronous validation function; see InputDialogOptions.ValidateAsync.
+func (b *InputDialogBuilder) ValidateAsync(validate func(ctx context.Context, text string) error) *InputDialogBuilder {
+	b.opts.ValidateAsync = validate
+	return b
+}
+
+// ValidateDelay sets the debounce delay of live validation.
+func (b *InputDialogBuilder) ValidateDelay(delay time.Duration) *InputDialogBuilder {
+	b.opts.ValidateDelay = delay
+	return b
+}
+
+// Buttons replaces the default buttons, from left to right.
+func (b *InputDialogBuilder) Buttons(buttons ...Button) *InputDialogBuilder {
+	b.opts.Buttons = slices.Clone(buttons)
+	return b
+}
+
+// Timeout closes the dialog automatically after the given duration, applying action.
+func (b *InputDialogBuilder) Timeout(timeout time.Duration, action TimeoutAction) *InputDialogBuilder {
+	b.opts.Timeout, b.opts.TimeoutAction = timeout, action
+	return b
+}
+
+// Clone returns an independent copy of the builder.
+func (b *InputDialogBuilder) Clone() *InputDialogBuilder {
+	c := *b
+	c.opts = b.Options()
+	return &c
+}
+
+// Options returns the options configured so far.
+func (b *InputDialogBuilder) Options() InputDialogOptions {
+	opts := b.opts
+	opts.Buttons = slices.Clone(b.opts.Buttons)
+	return opts
+}
+
+// Show displays the dialog; see PromptInput.
+func (b *InputDialogBuilder) Show() (result string, canceled bool, err error) {
+	return b.ShowContext(context.Background())
+}
+
+// ShowContext displays the dialog and closes it when ctx is done, returning ctx.Err().
+func (b *InputDialogBuilder) ShowContext(ctx context.Context) (result string, canceled bool, err error) {
+	return PromptInputContext(ctx, b.Options())
+}
+
+// Run displays the dialog and returns its Result; see RunInput.
+func (b *InputDialogBuilder) Run(ctx context.Context) (Result, error) {
+	return RunInput(ctx, b.Options())
+}
+
+// PasswordDialogBuilder configures a password dialog step by step.
+//
+// Every setter modifies the builder and returns it, so that calls can be
+// chained. Use Clone to derive several dialogs from a partially configured
+// template without modifying it.
+type PasswordDialogBuilder struct {
+	opts PasswordDialogOptions
+}
+
+// NewPasswordDialog starts the configuration of a password dialog.
+func NewPasswordDialog() *PasswordDialogBuilder {
+	return &PasswordDialogBuilder{}
+}
+
+// Size sets the dimensions of the dialog window.
+func (b *PasswordDialogBuilder) Size(width, height float32) *PasswordDialogBuilder {
+	b.opts.Width, b.opts.Height = width, height
+	return b
+}
+
+// Title sets the window title.
+func (b *PasswordDialogBuilder) Title(title string) *PasswordDialogBuilder {
+	b.opts.Title = title
+	return b
+}
+
+// Label sets the prompt label.
+func (b *PasswordDialogBuilder) Label(label string) *PasswordDialogBuilder {
+	b.opts.Label = label
+	return b
+}
+
+// Description sets the additional description or help text.
+func (b *PasswordDialogBuilder) Description(description string) *PasswordDialogBuilder {
+	b.opts.Description = description
+	return b
+}
+
+// Confirmation sets whether the password has to be entered twice.
+func (b *PasswordDialogBuilder) Confirmation(confirmation bool) *PasswordDialogBuilder {
+	b.opts.Confirmation = confirmation
+	return b
+}
+
+// Buttons replaces the default buttons, from left to right.
+func (b *PasswordDialogBuilder) Buttons(buttons ...Button) *PasswordDialogBuilder {
+	b.opts.Buttons = slices.Clone(buttons)
+	return b
+}
+
+// Timeout closes the dialog automatically after the given duration, applying action.
+func (b *PasswordDialogBuilder) Timeout(timeout time.Duration, action TimeoutAction) *PasswordDialogBuilder {
+	b.opts.Timeout, b.opts.TimeoutAction = timeout, action
+	return b
+}
+
+// Clone returns an independent copy of the builder.
+func (b *PasswordDialogBuilder) Clone() *PasswordDialogBuilder {
+	c := *b
+	c.opts = b.Options()
+	return &c
+}
+
+// Options returns the options configured so far.
+func (b *PasswordDialogBuilder) Options() PasswordDialogOptions {
+	opts := b.opts
+	opts.Buttons = slices.Clone(b.opts.Buttons)
+	return opts
+}
+
+// Show displays the dialog; see PromptPassword.
+func (b *PasswordDialogBuilder) Show() (secret []byte, canceled bool, err error) {
+	return b.ShowContext(context.Background())
+}
+
+// ShowContext displays the dialog and closes it when ctx is done, returning ctx.Err().
+func (b *PasswordDialogBuilder) ShowContext(ctx context.Context) (secret []byte, canceled bool, err error) {
+	return PromptPasswordContext(ctx, b.Options())
+}
+
+// Run displays the dialog and returns its Result; see RunPassword.
+func (b *PasswordDialogBuilder) Run(ctx context.Context) (Result, error) {
+	return RunPassword(ctx, b.Options())
+}
+
+// SelectDialogBuilder configures a single-select dialog step by step.
+//
+// Every setter modifies the builder and returns it, so that calls can be
+// chained. Use Clone to derive several dialogs from a partially configured
+// template without modifying it.
+type SelectDialogBuilder struct {
+	opts SelectDialogOptions
+}
+
+// NewSelectDialog starts the configuration of a single-select dialog.
+func NewSelectDialog() *SelectDialogBuilder {
+	return &SelectDialogBuilder{}
+}
+
+// Size sets the dimensions of the dialog window.
+func (b *SelectDialogBuilder) Size(width, height float32) *SelectDialogBuilder {
+	b.opts.Width, b.opts.Height = width, height
+	return b
+}
+
+// Title sets the window title.
+func (b *SelectDialogBuilder) Title(title string) *SelectDialogBuilder {
+	b.opts.Title = title
+	return b
+}
+
+// Label sets the prompt label.
+func (b *SelectDialogBuilder) Label(label string) *SelectDialogBuilder {
+	b.opts.Label = label
+	return b
+}
+
+// Description sets the additional description or help text.
+func (b *SelectDialogBuilder) Description(description string) *SelectDialogBuilder {
+	b.opts.Description = description
+	return b
+}
+
+// Choices sets the available options, replacing any previously set.
+func (b *SelectDialogBuilder) Choices(choices ...string) *SelectDialogBuilder {
+	b.opts.Choices = slices.Clone(choices)
+	return b
+}
+
+// Default sets the option pre-selected when the dialog opens.
+func (b *SelectDialogBuilder) Default(selection string) *SelectDialogBuilder {
+	b.opts.DefaultSelection = selection
+	return b
+}
+
+// AllowCustomEntry sets whether the user may enter a custom value.
+func (b *SelectDialogBuilder) AllowCustomEntry(allow bool) *SelectDialogBuilder {
+	b.opts.AllowCustomEntry = allow
+	return b
+}
+
+// Searchable shows a search field above the choices, matching them according to mode.
+func (b *SelectDialogBuilder) Searchable(mode MatchMode) *SelectDialogBuilder {
+	b.opts.Searchable, b.opts.SearchMode = true, mode
+	return b
+}
+
+// Buttons replaces the default buttons, from left to right.
+func (b *SelectDialogBuilder) Buttons(buttons ...Button) *SelectDialogBuilder {
+	b.opts.Buttons = slices.Clone(buttons)
+	return b
+}
+
+// Timeout closes the dialog automatically after the given duration, applying action.
+func (b *SelectDialogBuilder) Timeout(timeout time.Duration, action TimeoutAction) *SelectDialogBuilder {
+	b.opts.Timeout, b.opts.TimeoutAction = timeout, action
+	return b
+}
+
+// Clone returns an independent copy of the builder.
+func (b *SelectDialogBuilder) Clone() *SelectDialogBuilder {
+	c := *b
+	c.opts = b.Options()
+	return &c
+}
+
+// Options returns the options configured so far.
+func (b *SelectDialogBuilder) Options() SelectDialogOptions {
+	opts := b.opts
+	opts.Buttons = slices.Clone(b.opts.Buttons)
+	opts.Choices = slices.Clone(b.opts.Choices)
+	return opts
+}
+
+// Show displays the dialog; see PromptSelect.
+func (b *SelectDialogBuilder) Show() (selected string, canceled bool, err error) {
+	return b.ShowContext(context.Background())
+}
+
+// ShowContext displays the dialog and closes it when ctx is done, returning ctx.Err().
+func (b *SelectDialogBuilder) ShowContext(ctx context.Context) (selected string, canceled bool, err error) {
+	return PromptSelectContext(ctx, b.Options())
+}
+
+// Run displays the dialog and returns its Result; see RunSelect.
+func (b *SelectDialogBuilder) Run(ctx context.Context) (Result, error) {
+	return RunSelect(ctx, b.Options())
+}
+
+// MultiSelectDialogBuilder configures a multi-select dialog step by step.
+//
+// Every setter modifies the builder and returns it, so that calls can be
+// chained. Use Clone to derive several dialogs from a partially configured
+// template without modifying it.
+type MultiSelectDialogBuilder struct {
+	opts MultiSelectDialogOptions
+}
+
+// NewMultiSelectDialog starts the configuration of a multi-select dialog.
+func NewMultiSelectDialog() *MultiSelectDialogBuilder {
+	return &MultiSelectDialogBuilder{}
+}
+
+// Size sets the dimensions of the dialog window.
+func (b *MultiSelectDialogBuilder) Size(width, height float32) *MultiSelectDialogBuilder {
+	b.opts.Width, b.opts.Height = width, height
+	return b
+}
+
+// Title sets the window title.
+func (b *MultiSelectDialogBuilder) Title(title string) *MultiSelectDialogBuilder {
+	b.opts.Title = title
+	return b
+}
+
+// Label sets the prompt label.
+func (b *MultiSelectDialogBuilder) Label(label string) *MultiSelectDialogBuilder {
+	b.opts.Label = label
+	return b
+}
+
+// Description sets the additional description or help text.
+func (b *MultiSelectDialogBuilder) Description(description string) *MultiSelectDialogBuilder {
+	b.opts.Description = description
+	return b
+}
+
+// Choices sets the available options, replacing any previously set.
+func (b *MultiSelectDialogBuilder) Choices(choices ...string) *MultiSelectDialogBuilder {
+	b.opts.Choices = slices.Clone(choices)
+	return b
+}
+
+// Defaults sets the options checked when the dialog opens.
+func (b *MultiSelectDialogBuilder) Defaults(selections ...string) *MultiSelectDialogBuilder {
+	b.opts.DefaultSelections = slices.Clone(selections)
+	return b
+}
+
+// Limits sets the minimum and maximum number of checked options; 0 means no maximum.
+func (b *MultiSelectDialogBuilder) Limits(minSelections, maxSelections int) *MultiSelectDialogBuilder {
+	b.opts.MinSelections, b.opts.MaxSelections = minSelections, maxSelections
+	return b
+}
+
+// Buttons replaces the default buttons, from left to right.
+func (b *MultiSelectDialogBuilder) Buttons(buttons ...Button) *MultiSelectDialogBuilder {
+	b.opts.Buttons = slices.Clone(buttons)
+	return b
+}
+
+// Timeout closes the dialog automatically after the given duration, applying action.
+func (b *MultiSelectDialogBuilder) Timeout(timeout time.Duration, action TimeoutAction) *MultiSelectDialogBuilder {
+	b.opts.Timeout, b.opts.TimeoutAction = timeout, action
+	return b
+}
+
+// Clone returns an independent copy of the builder.
+func (b *MultiSelectDialogBuilder) Clone() *MultiSelectDialogBuilder {
+	c := *b
+	c.opts = b.Options()
+	return &c
+}
+
+// Options returns the options configured so far.
+func (b *MultiSelectDialogBuilder) Options() MultiSelectDialogOptions {
+	opts := b.opts
+	opts.Buttons = slices.Clone(b.opts.Buttons)
+	opts.Choices = slices.Clone(b.opts.Choices)
+	opts.DefaultSelections = slices.Clone(b.opts.DefaultSelections)
+	return opts
+}
+
+// Show displays the dialog; see PromptMultiSelect.
+func (b *MultiSelectDialogBuilder) Show() (selected []string, canceled bool, err error) {
+	return b.ShowContext(context.Background())
+}
+
+// ShowContext displays the dialog and closes it when ctx is done, returning ctx.Err().
+func (b *MultiSelectDialogBuilder) ShowContext(ctx context.Context) (selected []string, canceled bool, err error) {
+	return PromptMultiSelectContext(ctx, b.Options())
+}
+
+// Run displays the dialog and returns its Result; see RunMultiSelect.
+func (b *MultiSelectDialogBuilder) Run(ctx context.Context) (Result, error) {
+	return RunMultiSelect(ctx, b.Options())
+}
+
+// BaseDialogBuilder configures a base dialog step by step.
+//
+// Every setter modifies the builder and returns it, so that calls can be
+// chained. Use Clone to derive several dialogs from a partially configured
+// template without modifying it.
+type BaseDialogBuilder struct {
+	opts BaseDialogOptions
+}
+
+// NewBaseDialog starts the configuration of a base dialog.
+func NewBaseDialog() *BaseDialogBuilder {
+	return &BaseDialogBuilder{}
+}
+
+// Size sets the dimensions of the dialog window.
+func (b *BaseDialogBuilder) Size(width, height float32) *BaseDialogBuilder {
+	b.opts.Width, b.opts.Height = width, height
+	return b
+}
+
+// Title sets the window title.
+func (b *BaseDialogBuilder) Title(title string) *BaseDialogBuilder {
+	b.opts.Title = title
+	return b
+}
+
+// Label sets the prompt label.
+func (b *BaseDialogBuilder) Label(label string) *BaseDialogBuilder {
+	b.opts.Label = label
+	return b
+}
+
+// Description sets the additional description or help text.
+func (b *BaseDialogBuilder) Description(description string) *BaseDialogBuilder {
+	b.opts.Description = description
+	return b
+}
+
+// Buttons replaces the default buttons, from left to right.
+func (b *BaseDialogBuilder) Buttons(buttons ...Button) *BaseDialogBuilder {
+	b.opts.Buttons = slices.Clone(buttons)
+	return b
+}
+
+// Timeout closes the dialog automatically after the given duration, applying action.
+func (b *BaseDialogBuilder) Timeout(timeout time.Duration, action TimeoutAction) *BaseDialogBuilder {
+	b.opts.Timeout, b.opts.TimeoutAction = timeout, action
+	return b
+}
+
+// Clone returns an independent copy of the builder.
+func (b *BaseDialogBuilder) Clone() *BaseDialogBuilder {
+	c := *b
+	c.opts = b.Options()
+	return &c
+}
+
+// Options returns the options configured so far.
+func (b *BaseDialogBuilder) Options() BaseDialogOptions {
+	opts := b.opts
+	opts.Buttons = slices.Clone(b.opts.Buttons)
+	return opts
+}
+
+// Show displays the dialog; see PromptBase.
+func (b *BaseDialogBuilder) Show() (confirmed bool, canceled bool, err error) {
+	return b.ShowContext(context.Background())
+}
+
+// ShowContext displays the dialog and closes it when ctx is done, returning ctx.Err().
+func (b *BaseDialogBuilder) ShowContext(ctx context.Context) (confirmed bool, canceled bool, err error) {
+	return PromptBaseContext(ctx, b.Options())
+}
+
+// Run displays the dialog and returns its Result; see RunBase.
+func (b *BaseDialogBuilder) Run(ctx context.Context) (Result, error) {
+	return RunBase(ctx, b.Options())
+}
+
+// MessageDialogBuilder configures a message dialog step by step.
+//
+// Every setter modifies the builder and returns it, so that calls can be
+// chained. Use Clone to derive several dialogs from a partially configured
+// template without modifying it.
+type MessageDialogBuilder struct {
+	severity Severity
+	opts     MessageDialogOptions
+}
+
+// NewMessageDialog starts the configuration of a message dialog of the given severity.
+func NewMessageDialog(severity Severity) *MessageDialogBuilder {
+	return &MessageDialogBuilder{severity: severity}
+}
+
+// Size sets the dimensions of the dialog window.
+func (b *MessageDialogBuilder) Size(width, height float32) *MessageDialogBuilder {
+	b.opts.Width, b.opts.Height = width, height
+	return b
+}
+
+// Title sets the window title.
+func (b *MessageDialogBuilder) Title(title string) *MessageDialogBuilder {
+	b.opts.Title = title
+	return b
+}
+
+// Label sets the heading.
+func (b *MessageDialogBuilder) Label(label string) *MessageDialogBuilder {
+	b.opts.Label = label
+	return b
+}
+
+// Message sets the message text.
+func (b *MessageDialogBuilder) Message(message string) *MessageDialogBuilder {
+	b.opts.Message = message
+	return b
+}
+
+// Buttons replaces the default buttons, from left to right.
+func (b *MessageDialogBuilder) Buttons(buttons ...Button) *MessageDialogBuilder {
+	b.opts.Buttons = slices.Clone(buttons)
+	return b
+}
+
+// Timeout closes the dialog automatically after the given duration, applying action.
+func (b *MessageDialogBuilder) Timeout(timeout time.Duration, action TimeoutAction) *MessageDialogBuilder {
+	b.opts.Timeout, b.opts.TimeoutAction = timeout, action
+	return b
+}
+
+// Clone returns an independent copy of the builder.
+func (b *MessageDialogBuilder) Clone() *MessageDialogBuilder {
+	c := *b
+	c.opts = b.Options()
+	return &c
+}
+
+// Options returns the options configured so far.
+func (b *MessageDialogBuilder) Options() MessageDialogOptions {
+	opts := b.opts
+	opts.Buttons = slices.Clone(b.opts.Buttons)
+	return opts
+}
+
+// Show displays the dialog; see ShowInfo and AskQuestion.
+// For questions, yes reports whether the user answered Yes.
+func (b *MessageDialogBuilder) Show() (yes bool, err error) {
+	if b.severity == SeverityQuestion {
+		return AskQuestion(b.Options())
+	}
+	err = showMessage(b.severity, b.Options())
+	return err == nil, err
+}
+
+// Run displays the dialog and returns its Result; see RunMessage.
+func (b *MessageDialogBuilder) Run(ctx context.Context) (Result, error) {
+	return RunMessage(ctx, b.severity, b.Options())
+}
+
+// OpenFileDialogBuilder configures a file open dialog step by step.
+//
+// Every setter modifies the builder and returns it, so that calls can be
+// chained. Use Clone to derive several dialogs from a partially configured
+// template without modifying it.
+type OpenFileDialogBuilder struct {
+	opts OpenFileDialogOptions
+}
+
+// NewOpenFileDialog starts the configuration of a file open dialog.
+func NewOpenFileDialog() *OpenFileDialogBuilder {
+	return &OpenFileDialogBuilder{}
+}
+
+// Size sets the dimensions of the dialog window.
+func (b *OpenFileDialogBuilder) Size(width, height float32) *OpenFileDialogBuilder {
+	b.opts.Width, b.opts.Height = width, height
+	return b
+}
+
+// Title sets the window title.
+func (b *OpenFileDialogBuilder) Title(title string) *OpenFileDialogBuilder {
+	b.opts.Title = title
+	return b
+}
+
+// Label sets the prompt label.
+func (b *OpenFileDialogBuilder) Label(label string) *OpenFileDialogBuilder {
+	b.opts.Label = label
+	return b
+}
+
+// Description sets the additional description or help text.
+func (b *OpenFileDialogBuilder) Description(description string) *OpenFileDialogBuilder {
+	b.opts.Description = description
+	return b
+}
+
+// FS sets the file system to browse instead of the real one.
+func (b *OpenFileDialogBuilder) FS(fsys fs.FS) *OpenFileDialogBuilder {
+	b.opts.FS = fsys
+	return b
+}
+
+// Dir sets the directory shown first.
+func (b *OpenFileDialogBuilder) Dir(dir string) *OpenFileDialogBuilder {
+	b.opts.Dir = dir
+	return b
+}
+
+// Filters sets the file filters, replacing any previously set.
+func (b *OpenFileDialogBuilder) Filters(filters ...FileFilter) *OpenFileDialogBuilder {
+	b.opts.Filters = cloneFilters(filters)
+	return b
+}
+
+// ShowHidden sets whether files and directories starting with a dot are listed.
+func (b *OpenFileDialogBuilder) ShowHidden(show bool) *OpenFileDialogBuilder {
+	b.opts.ShowHidden = show
+	return b
+}
+
+// Multiple sets whether several files can be selected.
+func (b *OpenFileDialogBuilder) Multiple(multiple bool) *OpenFileDialogBuilder {
+	b.opts.Multiple = multiple
+	return b
+}
+
+// Buttons replaces the default buttons, from left to right.
+func (b *OpenFileDialogBuilder) Buttons(buttons ...Button) *OpenFileDialogBuilder {
+	b.opts.Buttons = slices.Clone(buttons)
+	return b
+}
+
+// Timeout closes the dialog automatically after the given duration, applying action.
+func (b *OpenFileDialogBuilder) Timeout(timeout time.Duration, action TimeoutAction) *OpenFileDialogBuilder {
+	b.opts.Timeout, b.opts.TimeoutAction = timeout, action
+	return b
+}
+
+// Clone returns an independent copy of the builder.
+func (b *OpenFileDialogBuilder) Clone() *OpenFileDialogBuilder {
+	c := *b
+	c.opts = b.Options()
+	return &c
+}
+
+// Options returns the options configured so far.
+func (b *OpenFileDialogBuilder) Options() OpenFileDialogOptions {
+	opts := b.opts
+	opts.Buttons = slices.Clone(b.opts.Buttons)
+	opts.Filters = cloneFilters(b.opts.Filters)
+	return opts
+}
+
+// Show displays the dialog; see PromptOpenFile.
+func (b *OpenFileDialogBuilder) Show() (paths []string, canceled bool, err error) {
+	return b.ShowContext(context.Background())
+}
+
+// ShowContext displays the dialog and closes it when ctx is done, returning ctx.Err().
+func (b *OpenFileDialogBuilder) ShowContext(ctx context.Context) (paths []string, canceled bool, err error) {
+	return PromptOpenFileContext(ctx, b.Options())
+}
+
+// Run displays the dialog and returns its Result; see RunOpenFile.
+func (b *OpenFileDialogBuilder) Run(ctx context.Context) (Result, error) {
+	return RunOpenFile(ctx, b.Options())
+}
+
+// SaveFileDialogBuilder configures a save file dialog step by step.
+//
+// Every setter modifies the builder and returns it, so that calls can be
+// chained. Use Clone to derive several dialogs from a partially configured
+// template without modifying it.
+type SaveFileDialogBuilder struct {
+	opts SaveFileDialogOptions
+}
+
+// NewSaveFileDialog starts the configuration of a save file dialog.
+func NewSaveFileDialog() *SaveFileDialogBuilder {
+	return &SaveFileDialogBuilder{}
+}
+
+// Size sets the dimensions of the dialog window.
+func (b *SaveFileDialogBuilder) Size(width, height float32) *SaveFileDialogBuilder {
+	b.opts.Width, b.opts.Height = width, height
+	return b
+}
+
+// Title sets the window title.
+func (b *SaveFileDialogBuilder) Title(title string) *SaveFileDialogBuilder {
+	b.opts.Title = title
+	return b
+}
+
+// Label sets the prompt label.
+func (b *SaveFileDialogBuilder) Label(label string) *SaveFileDialogBuilder {
+	b.opts.Label = label
+	return b
+}
+
+// Description sets the additional description or help text.
+func (b *SaveFileDialogBuilder) Description(description string) *SaveFileDialogBuilder {
+	b.opts.Description = description
+	return b
+}
+
+// FS sets the file system to browse instead of the real one.
+func (b *SaveFileDialogBuilder) FS(fsys fs.FS) *SaveFileDialogBuilder {
+	b.opts.FS = fsys
+	return b
+}
+
+// Root confines the dialog to a directory of the real file system.
+func (b *SaveFileDialogBuilder) Root(root string) *SaveFileDialogBuilder {
+	b.opts.Root = root
+	return b
+}
+
+// Dir sets the directory shown first.
+func (b *SaveFileDialogBuilder) Dir(dir string) *SaveFileDialogBuilder {
+	b.opts.Dir = dir
+	return b
+}
+
+// Name sets the file name suggested when the dialog opens.
+func (b *SaveFileDialogBuilder) Name(name string) *SaveFileDialogBuilder {
+	b.opts.Name = name
+	return b
+}
+
+// Extension sets the extension appended to file names that don't end with it.
+func (b *SaveFileDialogBuilder) Extension(ext string) *SaveFileDialogBuilder {
+	b.opts.Extension = ext
+	return b
+}
+
+// Filters sets the file filters, replacing any previously set.
+func (b *SaveFileDialogBuilder) Filters(filters ...FileFilter) *SaveFileDialogBuilder {
+	b.opts.Filters = cloneFilters(filters)
+	return b
+}
+
+// ShowHidden sets whether files and directories starting with a dot are listed.
+func (b *SaveFileDialogBuilder) ShowHidden(show bool) *SaveFileDialogBuilder {
+	b.opts.ShowHidden = show
+	return b
+}
+
+// Buttons replaces the default buttons, from left to right.
+func (b *SaveFileDialogBuilder) Buttons(buttons ...Button) *SaveFileDialogBuilder {
+	b.opts.Buttons = slices.Clone(buttons)
+	return b
+}
+
+// Timeout closes the dialog automatically after the given duration, applying action.
+func (b *SaveFileDialogBuilder) Timeout(timeout time.Duration, action TimeoutAction) *SaveFileDialogBuilder {
+	b.opts.Timeout, b.opts.TimeoutAction = timeout, action
+	return b
+}
+
+// Clone returns an independent copy of the builder.
+func (b *SaveFileDialogBuilder) Clone() *SaveFileDialogBuilder {
+	c := *b
+	c.opts = b.Options()
+	return &c
+}
+
+// Options returns the options configured so far.
+func (b *SaveFileDialogBuilder) Options() SaveFileDialogOptions {
+	opts := b.opts
+	opts.Buttons = slices.Clone(b.opts.Buttons)
+	opts.Filters = cloneFilters(b.opts.Filters)
+	return opts
+}
+
+// Show displays the dialog; see PromptSaveFile.
+func (b *SaveFileDialogBuilder) Show() (path string, canceled bool, err error) {
+	return b.ShowContext(context.Background())
+}
+
+// ShowContext displays the dialog and closes it when ctx is done, returning ctx.Err().
+func (b *SaveFileDialogBuilder) ShowContext(ctx context.Context) (path string, canceled bool, err error) {
+	return PromptSaveFileContext(ctx, b.Options())
+}
+
+// Run displays the dialog and returns its Result; see RunSaveFile.
+func (b *SaveFileDialogBuilder) Run(ctx context.Context) (Result, error) {
+	return RunSaveFile(ctx, b.Options())
+}
+
+// DirectoryDialogBuilder configures a directory picker step by step.
+//
+// Every setter modifies the builder and returns it, so that calls can be
+// chained. Use Clone to derive several dialogs from a partially configured
+// template without modifying it.
+type DirectoryDialogBuilder struct {
+	opts DirectoryDialogOptions
+}
+
+// NewDirectoryDialog starts the configuration of a directory picker.
+func NewDirectoryDialog() *DirectoryDialogBuilder {
+	return &DirectoryDialogBuilder{}
+}
+
+// Size sets the dimensions of the dialog window.
+func (b *DirectoryDialogBuilder) Size(width, height float32) *DirectoryDialogBuilder {
+	b.opts.Width, b.opts.Height = width, height
+	return b
+}
+
+// Title sets the window title.
+func (b *DirectoryDialogBuilder) Title(title string) *DirectoryDialogBuilder {
+	b.opts.Title = title
+	return b
+}
+
+// Label sets the prompt label.
+func (b *DirectoryDialogBuilder) Label(label string) *DirectoryDialogBuilder {
+	b.opts.Label = label
+	return b
+}
+
+// Description sets the additional description or help text.
+func (b *DirectoryDialogBuilder) Description(description string) *DirectoryDialogBuilder {
+	b.opts.Description = description
+	return b
+}
+
+// FS sets the file system to browse instead of the real one.
+func (b *DirectoryDialogBuilder) FS(fsys fs.FS) *DirectoryDialogBuilder {
+	b.opts.FS = fsys
+	return b
+}
+
+// Root confines the dialog to a directory of the real file system.
+func (b *DirectoryDialogBuilder) Root(root string) *DirectoryDialogBuilder {
+	b.opts.Root = root
+	return b
+}
+
+// Dir sets the directory selected first.
+func (b *DirectoryDialogBuilder) Dir(dir string) *DirectoryDialogBuilder {
+	b.opts.Dir = dir
+	return b
+}
+
+// ShowHidden sets whether directories starting with a dot are listed.
+func (b *DirectoryDialogBuilder) ShowHidden(show bool) *DirectoryDialogBuilder {
+	b.opts.ShowHidden = show
+	return b
+}
+
+// MustBeEmpty sets whether only directories without entries are accepted.
+func (b *DirectoryDialogBuilder) MustBeEmpty(empty bool) *DirectoryDialogBuilder {
+	b.opts.MustBeEmpty = empty
+	return b
+}
+
+// MustBeWritable sets whether only directories in which files can be created are accepted.
+func (b *DirectoryDialogBuilder) MustBeWritable(writable bool) *DirectoryDialogBuilder {
+	b.opts.MustBeWritable = writable
+	return b
+}
+
+// Buttons replaces the default buttons, from left to right.
+func (b *DirectoryDialogBuilder) Buttons(buttons ...Button) *DirectoryDialogBuilder {
+	b.opts.Buttons = slices.Clone(buttons)
+	return b
+}
+
+// Timeout closes the dialog automatically after the given duration, applying action.
+func (b *DirectoryDialogBuilder) Timeout(timeout time.Duration, action TimeoutAction) *DirectoryDialogBuilder {
+	b.opts.Timeout, b.opts.TimeoutAction = timeout, action
+	return b
+}
+
+// Clone returns an independent copy of the builder.
+func (b *DirectoryDialogBuilder) Clone() *DirectoryDialogBuilder {
+	c := *b
+	c.opts = b.Options()
+	return &c
+}
+
+// Options returns the options configured so far.
+func (b *DirectoryDialogBuilder) Options() DirectoryDialogOptions {
+	opts := b.opts
+	opts.Buttons = slices.Clone(b.opts.Buttons)
+	return opts
+}
+
+// Show displays the dialog; see PromptDirectory.
+func (b *DirectoryDialogBuilder) Show() (path string, canceled bool, err error) {
+	return b.ShowContext(context.Background())
+}
+
+// ShowContext displays the dialog and closes it when ctx is done, returning ctx.Err().
+func (b *DirectoryDialogBuilder) ShowContext(ctx context.Context) (path string, canceled bool, err error) {
+	return PromptDirectoryContext(ctx, b.Options())
+}
+
+// Run displays the dialog and returns its Result; see RunDirectory.
+func (b *DirectoryDialogBuilder) Run(ctx context.Context) (Result, error) {
+	return RunDirectory(ctx, b.Options())
+}
+
+// ProgressDialogBuilder configures a progress dialog step by step.
+//
+// Every setter modifies the builder and returns it, so that calls can be
+// chained. Use Clone to derive several dialogs from a partially configured
+// template without modifying it.
+type ProgressDialogBuilder struct {
+	opts ProgressDialogOptions
+}
+
+// NewProgressDialog starts the configuration of a progress dialog.
+func NewProgressDialog() *ProgressDialogBuilder {
+	return &ProgressDialogBuilder{}
+}
+
+// Size sets the dimensions of the dialog window.
+func (b *ProgressDialogBuilder) Size(width, height float32) *ProgressDialogBuilder {
+	b.opts.Width, b.opts.Height = width, height
+	return b
+}
+
+// Title sets the window title.
+func (b *ProgressDialogBuilder) Title(title string) *ProgressDialogBuilder {
+	b.opts.Title = title
+	return b
+}
+
+// Label sets the prompt label.
+func (b *ProgressDialogBuilder) Label(label string) *ProgressDialogBuilder {
+	b.opts.Label = label
+	return b
+}
+
+// Description sets the additional description or help text.
+func (b *ProgressDialogBuilder) Description(description string) *ProgressDialogBuilder {
+	b.opts.Description = description
+	return b
+}
+
+// Text sets the initial status text.
+func (b *ProgressDialogBuilder) Text(text string) *ProgressDialogBuilder {
+	b.opts.Text = text
+	return b
+}
+
+// Percent sets the initial progress, between 0 and 100.
+func (b *ProgressDialogBuilder) Percent(percent float64) *ProgressDialogBuilder {
+	b.opts.Percent = percent
+	return b
+}
+
+// Pulsate sets whether a pulsating bar is shown until the first progress update.
+func (b *ProgressDialogBuilder) Pulsate(pulsate bool) *ProgressDialogBuilder {
+	b.opts.Pulsate = pulsate
+	return b
+}
+
+// AutoClose sets whether the dialog closes as soon as the work is complete.
+func (b *ProgressDialogBuilder) AutoClose(autoClose bool) *ProgressDialogBuilder {
+	b.opts.AutoClose = autoClose
+	return b
+}
+
+// Buttons replaces the default buttons, from left to right.
+func (b *ProgressDialogBuilder) Buttons(buttons ...Button) *ProgressDialogBuilder {
+	b.opts.Buttons = slices.Clone(buttons)
+	return b
+}
+
+// Timeout closes the dialog automatically after the given duration, applying action.
+func (b *ProgressDialogBuilder) Timeout(timeout time.Duration, action TimeoutAction) *ProgressDialogBuilder {
+	b.opts.Timeout, b.opts.TimeoutAction = timeout, action
+	return b
+}
+
+// Clone returns an independent copy of the builder.
+func (b *ProgressDialogBuilder) Clone() *ProgressDialogBuilder {
+	c := *b
+	c.opts = b.Options()
+	return &c
+}
+
+// Options returns the options configured so far.
+func (b *ProgressDialogBuilder) Options() ProgressDialogOptions {
+	opts := b.opts
+	opts.Buttons = slices.Clone(b.opts.Buttons)
+	return opts
+}
+
+// Start displays the dialog and returns immediately; see StartProgress.
+func (b *ProgressDialogBuilder) Start(ctx context.Context) (*Progress, context.Context) {
+	return StartProgress(ctx, b.Options())
+}
+
+// FormDialogBuilder configures a form dialog step by step.
+//
+// Every setter modifies the builder and returns it, so that calls can be
+// chained. Use Clone to derive several dialogs from a partially configured
+// template without modifying it.
+type FormDialogBuilder struct {
+	opts FormDialogOptions
+}
+
+// NewFormDialog starts the configuration of a form dialog.
+func NewFormDialog() *FormDialogBuilder {
+	return &FormDialogBuilder{}
+}
+
+// Size sets the dimensions of the dialog window.
+func (b *FormDialogBuilder) Size(width, height float32) *FormDialogBuilder {
+	b.opts.Width, b.opts.Height = width, height
+	return b
+}
+
+// Title sets the window title.
+func (b *FormDialogBuilder) Title(title string) *FormDialogBuilder {
+	b.opts.Title = title
+	return b
+}
+
+// Label sets the prompt label.
+func (b *FormDialogBuilder) Label(label string) *FormDialogBuilder {
+	b.opts.Label = label
+	return b
+}
+
+// Description sets the additional description or help text.
+func (b *FormDialogBuilder) Description(description string) *FormDialogBuilder {
+	b.opts.Description = description
+	return b
+}
+
+// Fields sets the fields of the form, replacing any previously set.
+func (b *FormDialogBuilder) Fields(fields ...FormField) *FormDialogBuilder {
+	b.opts.Fields = cloneFields(fields)
+	return b
+}
+
+// Validate sets the validation function for the values of all fields.
+func (b *FormDialogBuilder) Validate(validate func(values map[string]any) error) *FormDialogBuilder {
+	b.opts.Validate = validate
+	return b
+}
+
+// Buttons replaces the default buttons, from left to right.
+func (b *FormDialogBuilder) Buttons(buttons ...Button) *FormDialogBuilder {
+	b.opts.Buttons = slices.Clone(buttons)
+	return b
+}
+
+// Timeout closes the dialog automatically after the given duration, applying action.
+func (b *FormDialogBuilder) Timeout(timeout time.Duration, action TimeoutAction) *FormDialogBuilder {
+	b.opts.Timeout, b.opts.TimeoutAction = timeout, action
+	return b
+}
+
+// Clone returns an independent copy of the builder.
+func (b *FormDialogBuilder) Clone() *FormDialogBuilder {
+	c := *b
+	c.opts = b.Options()
+	return &c
+}
+
+// Options returns the options configured so far.
+func (b *FormDialogBuilder) Options() FormDialogOptions {
+	opts := b.opts
+	opts.Buttons = slices.Clone(b.opts.Buttons)
+	opts.Fields = cloneFields(b.opts.Fields)
+	return opts
+}
+
+// Show displays the dialog; see PromptForm.
+func (b *FormDialogBuilder) Show() (values map[string]any, canceled bool, err error) {
+	return b.ShowContext(context.Background())
+}
+
+// ShowContext displays the dialog and closes it when ctx is done, returning ctx.Err().
+func (b *FormDialogBuilder) ShowContext(ctx context.Context) (values map[string]any, canceled bool, err error) {
+	return PromptFormContext(ctx, b.Options())
+}
+
+// Run displays the dialog and returns its Result; see RunForm.
+func (b *FormDialogBuilder) Run(ctx context.Context) (Result, error) {
+	return RunForm(ctx, b.Options())
+}
+
+// cloneFilters copies filters, including their patterns.
+func cloneFilters(filters []FileFilter) []FileFilter {
+	filters = slices.Clone(filters)
+	for i := range filters {
+		filters[i].Patterns = slices.Clone(filters[i].Patterns)
+	}
+	return filters
+}
+
+// cloneFields copies fields, including their choices.
+func cloneFields(fields []FormField) []FormField {
+	fields = slices.Clone(fields)
+	for i := range fields {
+		fields[i].Choices = slices.Clone(fields[i].Choices)
+	}
+	return fields
+}
@@ -0,0 +1,123 @@
+package dialog
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBuilderSetters(t *testing.T) {
+	choices := []string{"a", "b"}
+	buttons := []Button{{Label: "OK"}}
+	filters := []FileFilter{{Name: "Text", Patterns: []string{"*.txt"}}}
+	fields := []FormField{{Name: "level", Kind: FieldCombo, Choices: []string{"low", "high"}}}
+
+	sel := NewSelectDialog().Choices(choices...).Buttons(buttons...)
+	multi := NewMultiSelectDialog().Choices(choices...).Defaults(choices...)
+	open := NewOpenFileDialog().Filters(filters...)
+	form := NewFormDialog().Fields(fields...)
+
+	// Changing the arguments afterwards doesn't change the builders.
+	choices[0] = "x"
+	buttons[0].Label = "x"
+	filters[0].Patterns[0] = "x"
+	fields[0].Choices[0] = "x"
+
+	if got := sel.Options(); got.Choices[0] != "a" || got.Buttons[0].Label != "OK" {
+		t.Errorf("select options = %+v", got)
+	}
+	if got := multi.Options(); got.Choices[0] != "a" || got.DefaultSelections[0] != "a" {
+		t.Errorf("multi-select options = %+v", got)
+	}
+	if got := open.Options(); got.Filters[0].Patterns[0] != "*.txt" {
+		t.Errorf("open file filters = %+v", got.Filters)
+	}
+	if got := form.Options(); got.Fields[0].Choices[0] != "low" {
+		t.Errorf("form fields = %+v", got.Fields)
+	}
+}
+
+func TestBuilderOptions(t *testing.T) {
+	sel := NewSelectDialog().Choices("a", "b").Buttons(Button{Label: "OK"})
+	opts := sel.Options()
+	opts.Choices[0] = "x"
+	opts.Buttons[0].Label = "x"
+	if got := sel.Options(); got.Choices[0] != "a" || got.Buttons[0].Label != "OK" {
+		t.Errorf("changing the options changed the builder: %+v", got)
+	}
+
+	save := NewSaveFileDialog().Filters(FileFilter{Name: "Text", Patterns: []string{"*.txt"}})
+	save.Options().Filters[0].Patterns[0] = "x"
+	if got := save.Options().Filters[0].Patterns[0]; got != "*.txt" {
+		t.Errorf("changing the filters changed the builder: %q", got)
+	}
+
+	form := NewFormDialog().Fields(FormField{Name: "level", Kind: FieldCombo, Choices: []string{"low"}})
+	form.Options().Fields[0].Choices[0] = "x"
+	if got := form.Options().Fields[0].Choices[0]; got != "low" {
+		t.Errorf("changing the fields changed the builder: %q", got)
+	}
+}
+
+func TestBuilderClone(t *testing.T) {
+	tests := []struct {
+		name   string
+		source func() any
+		clone  func(any) // derives and modifies a clone
+	}{
+		{"input", func() any { return NewInputDialog().Title("A").Buttons(Button{Label: "OK"}) }, func(b any) {
+			c := b.(*InputDialogBuilder).Clone().Title("B")
+			c.opts.Buttons[0].Label = "x"
+		}},
+		{"password", func() any { return NewPasswordDialog().Title("A").Buttons(Button{Label: "OK"}) }, func(b any) {
+			c := b.(*PasswordDialogBuilder).Clone().Title("B")
+			c.opts.Buttons[0].Label = "x"
+		}},
+		{"select", func() any { return NewSelectDialog().Choices("a").Buttons(Button{Label: "OK"}) }, func(b any) {
+			c := b.(*SelectDialogBuilder).Clone().Title("B")
+			c.opts.Choices[0] = "x"
+			c.opts.Buttons[0].Label = "x"
+		}},
+		{"multi-select", func() any { return NewMultiSelectDialog().Choices("a").Defaults("a") }, func(b any) {
+			c := b.(*MultiSelectDialogBuilder).Clone().Title("B")
+			c.opts.Choices[0] = "x"
+			c.opts.DefaultSelections[0] = "x"
+		}},
+		{"base", func() any { return NewBaseDialog().Title("A").Buttons(Button{Label: "OK"}) }, func(b any) {
+			c := b.(*BaseDialogBuilder).Clone().Title("B")
+			c.opts.Buttons[0].Label = "x"
+		}},
+		{"message", func() any { return NewMessageDialog(SeverityInfo).Title("A").Buttons(Button{Label: "OK"}) }, func(b any) {
+			c := b.(*MessageDialogBuilder).Clone().Title("B")
+			c.opts.Buttons[0].Label = "x"
+		}},
+		{"open file", func() any { return NewOpenFileDialog().Filters(FileFilter{Patterns: []string{"*.txt"}}) }, func(b any) {
+			c := b.(*OpenFileDialogBuilder).Clone().Title("B")
+			c.opts.Filters[0].Patterns[0] = "x"
+		}},
+		{"save file", func() any { return NewSaveFileDialog().Filters(FileFilter{Patterns: []string{"*.txt"}}) }, func(b any) {
+			c := b.(*SaveFileDialogBuilder).Clone().Title("B")
+			c.opts.Filters[0].Patterns[0] = "x"
+		}},
+		{"directory", func() any { return NewDirectoryDialog().Title("A").Buttons(Button{Label: "OK"}) }, func(b any) {
+			c := b.(*DirectoryDialogBuilder).Clone().Title("B")
+			c.opts.Buttons[0].Label = "x"
+		}},
+		{"progress", func() any { return NewProgressDialog().Title("A").Buttons(Button{Label: "OK"}) }, func(b any) {
+			c := b.(*ProgressDialogBuilder).Clone().Title("B")
+			c.opts.Buttons[0].Label = "x"
+		}},
+		{"form", func() any { return NewFormDialog().Fields(FormField{Name: "level", Choices: []string{"low"}}) }, func(b any) {
+			c := b.(*FormDialogBuilder).Clone().Title("B")
+			c.opts.Fields[0].Choices[0] = "x"
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source := tt.source()
+			tt.clone(source)
+			if want := tt.source(); !reflect.DeepEqual(source, want) {
+				t.Errorf("modifying the clone changed the source: %+v, want %+v", source, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package dialog
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	internaldialog "github.com/gesellix/gioui-dialog/internal/dialog"
+)
+
+func TestContextDone(t *testing.T) {
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	expired, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancel()
+
+	// The dialogs aren't shown, since ctx is done before.
+	calls := map[string]func(ctx context.Context) (bool, error){
+		"RunInput": func(ctx context.Context) (bool, error) {
+			_, err := RunInput(ctx, InputDialogOptions{})
+			return false, err
+		},
+		"RunMessage": func(ctx context.Context) (bool, error) {
+			_, err := RunMessage(ctx, SeverityQuestion, MessageDialogOptions{})
+			return false, err
+		},
+		"PromptInputContext": func(ctx context.Context) (bool, error) {
+			_, canceled, err := PromptInputContext(ctx, InputDialogOptions{})
+			return canceled, err
+		},
+		"PromptSelectContext": func(ctx context.Context) (bool, error) {
+			_, canceled, err := PromptSelectContext(ctx, SelectDialogOptions{Choices: []string{"a"}})
+			return canceled, err
+		},
+		"PromptBaseContext": func(ctx context.Context) (bool, error) {
+			_, canceled, err := PromptBaseContext(ctx, BaseDialogOptions{})
+			return canceled, err
+		},
+		"Builder.ShowContext": func(ctx context.Context) (bool, error) {
+			_, canceled, err := NewInputDialog().ShowContext(ctx)
+			return canceled, err
+		},
+		"Builder.Run": func(ctx context.Context) (bool, error) {
+			_, err := NewPasswordDialog().Run(ctx)
+			return false, err
+		},
+	}
+	for name, call := range calls {
+		for _, ctx := range []context.Context{canceled, expired} {
+			wasCanceled, err := call(ctx)
+			if wasCanceled || !errors.Is(err, ctx.Err()) || errors.Is(err, ErrCanceled) || errors.Is(err, ErrDismissed) {
+				t.Errorf("%s with %v = %t, %v, want %v", name, ctx.Err(), wasCanceled, err, ctx.Err())
+			}
+		}
+	}
+}
+
+func TestContextDoneResult(t *testing.T) {
+	// The result of a dialog that was closed when ctx was done.
+	for _, cause := range []error{context.Canceled, context.DeadlineExceeded} {
+		res := newResult(internaldialog.Result{Outcome: internaldialog.Error, Err: cause})
+		if err := res.asError(); err != cause {
+			t.Errorf("asError() = %v, want %v", err, cause)
+		}
+		if _, canceled, err := promptResult(res, TimeoutCancel); canceled || err != cause {
+			t.Errorf("promptResult() = %t, %v, want %v", canceled, err, cause)
+		}
+	}
+}
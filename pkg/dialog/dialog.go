@@ -1,9 +1,53 @@
 package dialog
 
 import (
+	"context"
+	"time"
+
 	internaldialog "github.com/gesellix/gioui-dialog/internal/dialog"
 )
 
+// TimeoutAction defines how a dialog is closed when its Timeout expires.
+type TimeoutAction = internaldialog.TimeoutAction
+
+const (
+	// TimeoutCancel cancels the dialog when the timeout expires.
+	TimeoutCancel = internaldialog.TimeoutCancel
+	// TimeoutConfirm confirms the dialog with its default value when the timeout expires.
+	TimeoutConfirm = internaldialog.TimeoutConfirm
+)
+
+// ButtonRole defines what a dialog button does, and how it looks.
+type ButtonRole = internaldialog.ButtonRole
+
+const (
+	// RoleAffirmative accepts the input and confirms the dialog, e.g. OK or Save.
+	RoleAffirmative = internaldialog.RoleAffirmative
+	// RoleNegative cancels the dialog, e.g. Cancel.
+	RoleNegative = internaldialog.RoleNegative
+	// RoleNeutral closes the dialog without accepting the input, e.g. Later or Skip.
+	// The outcome is Canceled, and Result.Button tells the buttons apart.
+	RoleNeutral = internaldialog.RoleNeutral
+	// RoleDestructive confirms the dialog like RoleAffirmative, for actions that can't be undone,
+	// e.g. Delete or Discard. The button is drawn in red.
+	RoleDestructive = internaldialog.RoleDestructive
+)
+
+// Button describes a dialog button: its Label, its Role, whether Enter presses it (Default)
+// and whether Escape presses it (Cancel).
+type Button = internaldialog.Button
+
+// MatchMode defines how the search field of a select dialog matches the choices.
+type MatchMode = internaldialog.MatchMode
+
+const (
+	// MatchSubstring keeps the choices that contain the query, ignoring case.
+	MatchSubstring = internaldialog.MatchSubstring
+	// MatchFuzzy keeps the choices that contain the characters of the query in order,
+	// ignoring case, and lists the closest matches first.
+	MatchFuzzy = internaldialog.MatchFuzzy
+)
+
 // InputDialogOptions holds the configuration for a text-input dialog.
 type InputDialogOptions struct {
 	Width, Height float32            // Dimensions of the dialog window
@@ -12,15 +56,110 @@ type InputDialogOptions struct {
 	Description   string             // Additional description or help text
 	DefaultText   string             // Initial text shown in the input field
 	Validate      func(string) error // Optional validation function; return an error on invalid input
+
+	// ValidateLive is an optional validation function that runs while the user is typing,
+	// once no further edit happened for ValidateDelay.
+	ValidateLive func(string) error
+	// ValidateAsync is an optional validation function that runs in the background after
+	// ValidateLive passed, e.g. to check the input against an index or a remote service.
+	// Its context is canceled when the input changes again or the dialog closes.
+	ValidateAsync func(ctx context.Context, text string) error
+	// ValidateDelay is the debounce delay for ValidateLive and ValidateAsync (default 300ms).
+	ValidateDelay time.Duration
+
+	// Buttons replace the default buttons, from left to right (optional).
+	Buttons []Button
+
+	// Timeout closes the dialog automatically after the given duration (optional).
+	// The remaining seconds are shown on the button that TimeoutAction triggers,
+	// and the countdown stops as soon as the user interacts with the dialog.
+	Timeout       time.Duration
+	TimeoutAction TimeoutAction // How the dialog is closed when Timeout expires
 }
 
 // PromptInput displays a text-input dialog according to the provided options.
 // It returns the entered text, a flag indicating whether the dialog was canceled, and any error.
+// If the dialog timed out, the error is ErrTimeout.
 func PromptInput(opts InputDialogOptions) (result string, canceled bool, err error) {
+	return PromptInputContext(context.Background(), opts)
+}
+
+// PromptInputContext is like PromptInput, but closes the dialog when ctx is done.
+// In that case, the returned error is ctx.Err(), i.e. context.Canceled or context.DeadlineExceeded.
+func PromptInputContext(ctx context.Context, opts InputDialogOptions) (result string, canceled bool, err error) {
+	res, _ := RunInput(ctx, opts)
+	return promptResult(res, opts.TimeoutAction)
+}
+
+// RunInput displays a text-input dialog and closes it when ctx is done.
+// The returned error is nil if the user confirmed the dialog, and ErrCanceled, ErrDismissed,
+// ErrTimeout or the cause of the failure otherwise.
+func RunInput(ctx context.Context, opts InputDialogOptions) (Result, error) {
+	return run(ctx, newInputDialog(opts))
+}
+
+func newInputDialog(opts InputDialogOptions) internaldialog.Dialog {
 	dlg := internaldialog.NewInputDialog(
 		opts.Width, opts.Height,
 		opts.Title, opts.Label, opts.Description, opts.DefaultText, opts.Validate)
-	return dlg.Show()
+	dlg.ValidateLive = opts.ValidateLive
+	dlg.ValidateAsync = opts.ValidateAsync
+	dlg.ValidateDelay = opts.ValidateDelay
+	dlg.Buttons = opts.Buttons
+	dlg.Timeout, dlg.TimeoutAction = opts.Timeout, opts.TimeoutAction
+	return dlg
+}
+
+// PasswordDialogOptions holds the configuration for a password (secret input) dialog.
+type PasswordDialogOptions struct {
+	Width, Height float32 // Dimensions of the dialog window
+	Title         string  // Window title
+	Label         string  // Prompt label
+	Description   string  // Additional description or help text
+	Confirmation  bool    // If true, the password has to be entered twice, e.g. when setting a new one
+
+	// Buttons replace the default buttons, from left to right (optional).
+	Buttons []Button
+
+	// Timeout closes the dialog automatically after the given duration (optional).
+	// The remaining seconds are shown on the button that TimeoutAction triggers,
+	// and the countdown stops as soon as the user interacts with the dialog.
+	Timeout       time.Duration
+	TimeoutAction TimeoutAction // How the dialog is closed when Timeout expires
+}
+
+// PromptPassword displays a password dialog with masked input according to the provided options.
+// It returns the entered secret, a flag indicating whether the dialog was canceled, and any error.
+// The secret is never converted to a string, and the dialog doesn't keep a copy of it,
+// so the caller can zero it after use, e.g. with clear(secret).
+// If the dialog timed out, the error is ErrTimeout.
+func PromptPassword(opts PasswordDialogOptions) (secret []byte, canceled bool, err error) {
+	return PromptPasswordContext(context.Background(), opts)
+}
+
+// PromptPasswordContext is like PromptPassword, but closes the dialog when ctx is done.
+// In that case, the returned error is ctx.Err(), i.e. context.Canceled or context.DeadlineExceeded.
+func PromptPasswordContext(ctx context.Context, opts PasswordDialogOptions) (secret []byte, canceled bool, err error) {
+	res, _ := RunPassword(ctx, opts)
+	_, canceled, err = promptResult(res, opts.TimeoutAction)
+	return res.Secret, canceled, err
+}
+
+// RunPassword displays a password dialog and closes it when ctx is done.
+// The entered secret is returned in Result.Secret.
+// The returned error is nil if the user confirmed the dialog, and ErrCanceled, ErrDismissed,
+// ErrTimeout or the cause of the failure otherwise.
+func RunPassword(ctx context.Context, opts PasswordDialogOptions) (Result, error) {
+	return run(ctx, newPasswordDialog(opts))
+}
+
+func newPasswordDialog(opts PasswordDialogOptions) internaldialog.Dialog {
+	dlg := internaldialog.NewPasswordDialog(
+		opts.Width, opts.Height,
+		opts.Title, opts.Label, opts.Description, opts.Confirmation)
+	dlg.Buttons = opts.Buttons
+	dlg.Timeout, dlg.TimeoutAction = opts.Timeout, opts.TimeoutAction
+	return dlg
 }
 
 // SelectDialogOptions holds the configuration for a single-selection dialog.
@@ -32,15 +171,107 @@ type SelectDialogOptions struct {
 	Choices          []string // Available options to select from
 	DefaultSelection string   // Option pre-selected when the dialog opens
 	AllowCustomEntry bool     // If true, allows the user to enter a custom value
+
+	// Searchable shows a search field above the choices that narrows the list as the user types.
+	// Matched characters are highlighted. Use it for long lists, e.g. branches, hosts or packages.
+	Searchable bool
+	SearchMode MatchMode // How the search field matches the choices (default MatchSubstring)
+
+	// Buttons replace the default buttons, from left to right (optional).
+	Buttons []Button
+
+	// Timeout closes the dialog automatically after the given duration (optional).
+	// The remaining seconds are shown on the button that TimeoutAction triggers,
+	// and the countdown stops as soon as the user interacts with the dialog.
+	Timeout       time.Duration
+	TimeoutAction TimeoutAction // How the dialog is closed when Timeout expires
 }
 
 // PromptSelect displays a single-select dialog according to the provided options.
 // It returns the selected item, a flag indicating whether the dialog was canceled, and any error.
+// If the dialog timed out, the error is ErrTimeout.
 func PromptSelect(opts SelectDialogOptions) (selected string, canceled bool, err error) {
+	return PromptSelectContext(context.Background(), opts)
+}
+
+// PromptSelectContext is like PromptSelect, but closes the dialog when ctx is done.
+// In that case, the returned error is ctx.Err(), i.e. context.Canceled or context.DeadlineExceeded.
+func PromptSelectContext(ctx context.Context, opts SelectDialogOptions) (selected string, canceled bool, err error) {
+	res, _ := RunSelect(ctx, opts)
+	return promptResult(res, opts.TimeoutAction)
+}
+
+// RunSelect displays a single-select dialog and closes it when ctx is done.
+// The returned error is nil if the user confirmed the dialog, and ErrCanceled, ErrDismissed,
+// ErrTimeout or the cause of the failure otherwise.
+func RunSelect(ctx context.Context, opts SelectDialogOptions) (Result, error) {
+	return run(ctx, newSelectDialog(opts))
+}
+
+func newSelectDialog(opts SelectDialogOptions) internaldialog.Dialog {
 	dlg := internaldialog.NewSelectDialog(
 		opts.Width, opts.Height,
 		opts.Title, opts.Label, opts.Description, opts.Choices, opts.DefaultSelection, opts.AllowCustomEntry)
-	return dlg.Show()
+	dlg.Buttons = opts.Buttons
+	dlg.Searchable, dlg.SearchMode = opts.Searchable, opts.SearchMode
+	dlg.Timeout, dlg.TimeoutAction = opts.Timeout, opts.TimeoutAction
+	return dlg
+}
+
+// MultiSelectDialogOptions holds the configuration for a multi-selection (checklist) dialog.
+type MultiSelectDialogOptions struct {
+	Width, Height     float32  // Dimensions of the dialog window
+	Title             string   // Window title
+	Label             string   // Prompt label
+	Description       string   // Additional description or help text
+	Choices           []string // Available options to select from
+	DefaultSelections []string // Options checked when the dialog opens
+	MinSelections     int      // Minimum number of checked options required to confirm (optional)
+	MaxSelections     int      // Maximum number of checked options allowed, 0 means no limit (optional)
+
+	// Buttons replace the default buttons, from left to right (optional).
+	Buttons []Button
+
+	// Timeout closes the dialog automatically after the given duration (optional).
+	// The remaining seconds are shown on the button that TimeoutAction triggers,
+	// and the countdown stops as soon as the user interacts with the dialog.
+	Timeout       time.Duration
+	TimeoutAction TimeoutAction // How the dialog is closed when Timeout expires
+}
+
+// PromptMultiSelect displays a multi-select dialog according to the provided options.
+// It returns the checked items in the order of Choices, a flag indicating whether the dialog was canceled,
+// and any error. If the dialog timed out, the error is ErrTimeout.
+func PromptMultiSelect(opts MultiSelectDialogOptions) (selected []string, canceled bool, err error) {
+	return PromptMultiSelectContext(context.Background(), opts)
+}
+
+// PromptMultiSelectContext is like PromptMultiSelect, but closes the dialog when ctx is done.
+// In that case, the returned error is ctx.Err(), i.e. context.Canceled or context.DeadlineExceeded.
+func PromptMultiSelectContext(ctx context.Context, opts MultiSelectDialogOptions) (selected []string, canceled bool, err error) {
+	res, _ := RunMultiSelect(ctx, opts)
+	_, canceled, err = promptResult(res, opts.TimeoutAction)
+	if res.Outcome == Canceled || res.Outcome == Error {
+		return nil, canceled, err
+	}
+	return res.Values, canceled, err
+}
+
+// RunMultiSelect displays a multi-select dialog and closes it when ctx is done.
+// The returned error is nil if the user confirmed the dialog, and ErrCanceled, ErrDismissed,
+// ErrTimeout or the cause of the failure otherwise.
+func RunMultiSelect(ctx context.Context, opts MultiSelectDialogOptions) (Result, error) {
+	return run(ctx, newMultiSelectDialog(opts))
+}
+
+func newMultiSelectDialog(opts MultiSelectDialogOptions) internaldialog.Dialog {
+	dlg := internaldialog.NewMultiSelectDialog(
+		opts.Width, opts.Height,
+		opts.Title, opts.Label, opts.Description, opts.Choices, opts.DefaultSelections,
+		opts.MinSelections, opts.MaxSelections)
+	dlg.Buttons = opts.Buttons
+	dlg.Timeout, dlg.TimeoutAction = opts.Timeout, opts.TimeoutAction
+	return dlg
 }
 
 // BaseDialogOptions holds the configuration for a basic dialog with just title, label, description and OK/Cancel buttons.
@@ -49,13 +280,137 @@ type BaseDialogOptions struct {
 	Title         string  // Window title
 	Label         string  // Prompt label
 	Description   string  // Additional description or help text
+
+	// Buttons replace the default buttons, from left to right (optional).
+	Buttons []Button
+
+	// Timeout closes the dialog automatically after the given duration (optional).
+	// The remaining seconds are shown on the button that TimeoutAction triggers,
+	// and the countdown stops as soon as the user interacts with the dialog.
+	Timeout       time.Duration
+	TimeoutAction TimeoutAction // How the dialog is closed when Timeout expires
 }
 
 // PromptBase displays a base dialog according to the provided options.
 // It returns whether the dialog was confirmed, a flag indicating whether it was canceled, and any error.
+// If the dialog timed out, the error is ErrTimeout.
 func PromptBase(opts BaseDialogOptions) (confirmed bool, canceled bool, err error) {
+	return PromptBaseContext(context.Background(), opts)
+}
+
+// PromptBaseContext is like PromptBase, but closes the dialog when ctx is done.
+// In that case, the returned error is ctx.Err(), i.e. context.Canceled or context.DeadlineExceeded.
+func PromptBaseContext(ctx context.Context, opts BaseDialogOptions) (confirmed bool, canceled bool, err error) {
+	res, _ := RunBase(ctx, opts)
+	_, canceled, err = promptResult(res, opts.TimeoutAction)
+	confirmed = res.Outcome == Confirmed || res.Outcome == TimedOut && opts.TimeoutAction == TimeoutConfirm
+	return confirmed, canceled, err
+}
+
+// RunBase displays a base dialog and closes it when ctx is done.
+// The returned error is nil if the user confirmed the dialog, and ErrCanceled, ErrDismissed,
+// ErrTimeout or the cause of the failure otherwise.
+func RunBase(ctx context.Context, opts BaseDialogOptions) (Result, error) {
+	return run(ctx, newBaseDialog(opts))
+}
+
+func newBaseDialog(opts BaseDialogOptions) internaldialog.Dialog {
 	dlg := internaldialog.NewBaseDialog(
 		opts.Width, opts.Height,
 		opts.Title, opts.Label, opts.Description)
-	return dlg.Show()
+	dlg.Buttons = opts.Buttons
+	dlg.Timeout, dlg.TimeoutAction = opts.Timeout, opts.TimeoutAction
+	return dlg
+}
+
+// Severity defines the icon and buttons of a message dialog.
+type Severity = internaldialog.Severity
+
+const (
+	// SeverityInfo shows an information with a single OK button.
+	SeverityInfo = internaldialog.SeverityInfo
+	// SeverityWarning shows a warning with a single OK button.
+	SeverityWarning = internaldialog.SeverityWarning
+	// SeverityError shows an error with a single OK button.
+	SeverityError = internaldialog.SeverityError
+	// SeverityQuestion asks a question with Yes and No buttons.
+	SeverityQuestion = internaldialog.SeverityQuestion
+)
+
+// MessageDialogOptions holds the configuration for a message dialog.
+type MessageDialogOptions struct {
+	Width, Height float32 // Dimensions of the dialog window
+	Title         string  // Window title
+	Label         string  // Heading
+	Message       string  // Message text, which can be selected and copied
+
+	// Buttons replace the default buttons, from left to right (optional).
+	Buttons []Button
+
+	// Timeout closes the dialog automatically after the given duration (optional).
+	// The remaining seconds are shown on the button that TimeoutAction triggers,
+	// and the countdown stops as soon as the user interacts with the dialog.
+	Timeout       time.Duration
+	TimeoutAction TimeoutAction // How the dialog is closed when Timeout expires
+}
+
+// ShowInfo displays an information message with an OK button.
+// It returns once the user closed the dialog. If the dialog timed out, the error is ErrTimeout.
+func ShowInfo(opts MessageDialogOptions) error {
+	return showMessage(SeverityInfo, opts)
+}
+
+// ShowWarning displays a warning message with an OK button.
+// It returns once the user closed the dialog. If the dialog timed out, the error is ErrTimeout.
+func ShowWarning(opts MessageDialogOptions) error {
+	return showMessage(SeverityWarning, opts)
+}
+
+// ShowError displays an error message with an OK button.
+// It returns once the user closed the dialog. If the dialog timed out, the error is ErrTimeout.
+func ShowError(opts MessageDialogOptions) error {
+	return showMessage(SeverityError, opts)
+}
+
+func showMessage(severity Severity, opts MessageDialogOptions) error {
+	res, err := RunMessage(context.Background(), severity, opts)
+	if res.Outcome == Canceled || res.Outcome == Dismissed {
+		// Closing a message with Escape or through the window manager is fine.
+		return nil
+	}
+	return err
+}
+
+// AskQuestion displays a question with Yes and No buttons.
+// It returns whether the user answered Yes. If the dialog timed out, the error is ErrTimeout,
+// and yes reports whether the TimeoutAction was TimeoutConfirm.
+func AskQuestion(opts MessageDialogOptions) (yes bool, err error) {
+	res, _ := RunMessage(context.Background(), SeverityQuestion, opts)
+	_, _, err = promptResult(res, opts.TimeoutAction)
+	yes = res.Outcome == Confirmed || res.Outcome == TimedOut && opts.TimeoutAction == TimeoutConfirm
+	return yes, err
+}
+
+// RunMessage displays a message dialog of the given severity and closes it when ctx is done.
+// The returned error is nil if the user clicked OK or Yes, and ErrCanceled, ErrDismissed,
+// ErrTimeout or the cause of the failure otherwise.
+func RunMessage(ctx context.Context, severity Severity, opts MessageDialogOptions) (Result, error) {
+	return run(ctx, newMessageDialog(severity, opts))
+}
+
+func newMessageDialog(severity Severity, opts MessageDialogOptions) internaldialog.Dialog {
+	dlg := internaldialog.NewMessageDialog(
+		opts.Width, opts.Height,
+		opts.Title, opts.Label, opts.Message, severity)
+	if opts.Buttons != nil {
+		dlg.Buttons = opts.Buttons
+	}
+	dlg.Timeout, dlg.TimeoutAction = opts.Timeout, opts.TimeoutAction
+	return dlg
+}
+
+// run shows d in a window of its own and converts its result.
+func run(ctx context.Context, d internaldialog.Dialog) (Result, error) {
+	res := newResult(internaldialog.Run(ctx, d))
+	return res, res.asError()
 }
@@ -0,0 +1,14 @@
+package dialog
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNewSelectDialog(t *testing.T) {
+	opts := NewSelectDialog().Choices("a", "b").Searchable(MatchFuzzy).Options()
+	d := reflect.ValueOf(newSelectDialog(opts)).Elem()
+	if searchable, mode := d.FieldByName("Searchable").Bool(), d.FieldByName("SearchMode").Interface(); !searchable || mode != MatchFuzzy {
+		t.Errorf("Searchable, SearchMode = %t, %v, want true, %v", searchable, mode, MatchFuzzy)
+	}
+}
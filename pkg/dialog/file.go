@@ -0,0 +1,314 @@
+package dialog
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	internaldialog "github.com/gesellix/gioui-dialog/internal/dialog"
+)
+
+// FileFilter limits the files a file dialog lists to those whose name matches one of Patterns,
+// e.g. {Name: "Images", Patterns: []string{"*.png", "*.jpg"}}. Patterns use the syntax of
+// path.Match and ignore case. Directories are always listed.
+type FileFilter = internaldialog.FileFilter
+
+// MkdirFS is a file system in which directories can be created. The save file dialog and
+// the directory picker offer a "New folder" action for such file systems, and for the real
+// file system.
+type MkdirFS = internaldialog.MkdirFS
+
+// OpenFileDialogOptions holds the configuration for a file open dialog.
+type OpenFileDialogOptions struct {
+	Width, Height float32 // Dimensions of the dialog window
+	Title         string  // Window title
+	Label         string  // Prompt label
+	Description   string  // Additional description or help text
+
+	// FS is the file system to browse, e.g. an fstest.MapFS or an embed.FS (optional).
+	// Without it, the dialog browses the real file system and returns paths of the
+	// operating system; with it, paths are slash-separated paths of FS.
+	FS fs.FS
+	// Dir is the directory shown first: a path of FS, or of the operating system if FS
+	// is nil (default: the root of FS, or the working directory).
+	Dir        string
+	Filters    []FileFilter // Filters the user can choose from; the first one is applied (optional)
+	ShowHidden bool         // Show files and directories starting with a dot
+	Multiple   bool         // Allow selecting several files of the same directory
+
+	// Buttons replace the default buttons, from left to right (optional).
+	Buttons []Button
+
+	// Timeout closes the dialog automatically after the given duration (optional).
+	// The remaining seconds are shown on the button that TimeoutAction triggers,
+	// and the countdown stops as soon as the user interacts with the dialog.
+	Timeout       time.Duration
+	TimeoutAction TimeoutAction // How the dialog is closed when Timeout expires
+}
+
+// PromptOpenFile displays a file open dialog according to the provided options.
+// It returns the selected files, a flag indicating whether the dialog was canceled,
+// and any error. If the dialog timed out, the error is ErrTimeout.
+func PromptOpenFile(opts OpenFileDialogOptions) (paths []string, canceled bool, err error) {
+	return PromptOpenFileContext(context.Background(), opts)
+}
+
+// PromptOpenFileContext is like PromptOpenFile, but closes the dialog when ctx is done.
+// In that case, the returned error is ctx.Err(), i.e. context.Canceled or context.DeadlineExceeded.
+func PromptOpenFileContext(ctx context.Context, opts OpenFileDialogOptions) (paths []string, canceled bool, err error) {
+	res, _ := RunOpenFile(ctx, opts)
+	_, canceled, err = promptResult(res, opts.TimeoutAction)
+	if res.Outcome == Canceled || res.Outcome == Error {
+		return nil, canceled, err
+	}
+	return res.Values, canceled, err
+}
+
+// RunOpenFile displays a file open dialog and closes it when ctx is done.
+// The selected files are returned in Result.Values, and the first one in Result.Value.
+// The returned error is nil if the user confirmed the dialog, and ErrCanceled, ErrDismissed,
+// ErrTimeout or the cause of the failure otherwise.
+func RunOpenFile(ctx context.Context, opts OpenFileDialogOptions) (Result, error) {
+	return run(ctx, newOpenFileDialog(opts))
+}
+
+func newOpenFileDialog(opts OpenFileDialogOptions) internaldialog.Dialog {
+	fsys, dir, root := fileSystem(opts.FS, "", opts.Dir)
+	dlg := internaldialog.NewOpenFileDialog(
+		opts.Width, opts.Height,
+		opts.Title, opts.Label, opts.Description, fsys, dir, opts.Filters, opts.Multiple)
+	dlg.Browser.Root = root
+	dlg.Browser.ShowHidden = opts.ShowHidden
+	if opts.Buttons != nil {
+		dlg.Buttons = opts.Buttons
+	}
+	dlg.Timeout, dlg.TimeoutAction = opts.Timeout, opts.TimeoutAction
+	return dlg
+}
+
+// SaveFileDialogOptions holds the configuration for a save file dialog.
+type SaveFileDialogOptions struct {
+	Width, Height float32 // Dimensions of the dialog window
+	Title         string  // Window title
+	Label         string  // Prompt label
+	Description   string  // Additional description or help text
+
+	// FS is the file system to browse, e.g. an fstest.MapFS (optional). If it implements
+	// MkdirFS, new folders can be created. Without it, the dialog browses the real file
+	// system and returns paths of the operating system; with it, paths are slash-separated
+	// paths of FS.
+	FS fs.FS
+	// Root confines the dialog to a directory of the real file system when FS is nil:
+	// the user can't browse above it, and paths that resolve to a location outside of it,
+	// e.g. through symbolic links, are rejected (optional).
+	Root string
+	// Dir is the directory shown first: a path of FS, or of the operating system if FS
+	// is nil (default: the root of FS, Root, or the working directory).
+	Dir string
+	// Name is the file name suggested when the dialog opens.
+	Name string
+	// Extension, e.g. ".txt", is appended to the file name unless it already ends with it.
+	// Without it, the extension of the active filter is appended if its first pattern is like "*.txt".
+	Extension  string
+	Filters    []FileFilter // Filters the user can choose from; the first one is applied (optional)
+	ShowHidden bool         // Show files and directories starting with a dot
+
+	// Buttons replace the default buttons, from left to right (optional).
+	Buttons []Button
+
+	// Timeout closes the dialog automatically after the given duration (optional).
+	// The remaining seconds are shown on the button that TimeoutAction triggers,
+	// and the countdown stops as soon as the user interacts with the dialog.
+	Timeout       time.Duration
+	TimeoutAction TimeoutAction // How the dialog is closed when Timeout expires
+}
+
+// PromptSaveFile displays a save file dialog according to the provided options.
+// If the chosen file exists, the user is asked whether to replace it.
+// It returns the path of the file, a flag indicating whether the dialog was canceled,
+// and any error. If the dialog timed out, the error is ErrTimeout.
+func PromptSaveFile(opts SaveFileDialogOptions) (path string, canceled bool, err error) {
+	return PromptSaveFileContext(context.Background(), opts)
+}
+
+// PromptSaveFileContext is like PromptSaveFile, but closes the dialog when ctx is done.
+// In that case, the returned error is ctx.Err(), i.e. context.Canceled or context.DeadlineExceeded.
+func PromptSaveFileContext(ctx context.Context, opts SaveFileDialogOptions) (path string, canceled bool, err error) {
+	res, _ := RunSaveFile(ctx, opts)
+	return promptResult(res, opts.TimeoutAction)
+}
+
+// RunSaveFile displays a save file dialog and closes it when ctx is done.
+// The path of the file is returned in Result.Value.
+// The returned error is nil if the user confirmed the dialog, and ErrCanceled, ErrDismissed,
+// ErrTimeout or the cause of the failure otherwise.
+func RunSaveFile(ctx context.Context, opts SaveFileDialogOptions) (Result, error) {
+	return run(ctx, newSaveFileDialog(opts))
+}
+
+func newSaveFileDialog(opts SaveFileDialogOptions) internaldialog.Dialog {
+	fsys, dir, root := fileSystem(opts.FS, opts.Root, opts.Dir)
+	dlg := internaldialog.NewSaveFileDialog(
+		opts.Width, opts.Height,
+		opts.Title, opts.Label, opts.Description, fsys, dir, opts.Name, opts.Filters)
+	dlg.Extension = opts.Extension
+	dlg.Browser.Root = root
+	dlg.Browser.ShowHidden = opts.ShowHidden
+	if opts.Buttons != nil {
+		dlg.Buttons = opts.Buttons
+	}
+	dlg.Timeout, dlg.TimeoutAction = opts.Timeout, opts.TimeoutAction
+	return dlg
+}
+
+// DirectoryDialogOptions holds the configuration for a directory picker.
+type DirectoryDialogOptions struct {
+	Width, Height float32 // Dimensions of the dialog window
+	Title         string  // Window title
+	Label         string  // Prompt label
+	Description   string  // Additional description or help text
+
+	// FS is the file system to browse, e.g. an fstest.MapFS (optional). If it implements
+	// MkdirFS, new folders can be created. Without it, the dialog browses the real file
+	// system and returns paths of the operating system; with it, paths are slash-separated
+	// paths of FS.
+	FS fs.FS
+	// Root confines the dialog to a directory of the real file system when FS is nil:
+	// only it and the directories below it are listed (optional).
+	Root string
+	// Dir is the directory selected first: a path of FS, or of the operating system if FS
+	// is nil (default: the root of FS, Root, or the working directory).
+	Dir        string
+	ShowHidden bool // Show directories starting with a dot
+
+	// MustBeEmpty only accepts directories without any entries.
+	MustBeEmpty bool
+	// MustBeWritable only accepts directories in which files can be created. With FS,
+	// that requires FS to implement MkdirFS. It is checked when the user confirms a
+	// directory, since the check creates a file in the real file system.
+	MustBeWritable bool
+
+	// Buttons replace the default buttons, from left to right (optional).
+	Buttons []Button
+
+	// Timeout closes the dialog automatically after the given duration (optional).
+	// The remaining seconds are shown on the button that TimeoutAction triggers,
+	// and the countdown stops as soon as the user interacts with the dialog.
+	Timeout       time.Duration
+	TimeoutAction TimeoutAction // How the dialog is closed when Timeout expires
+}
+
+// PromptDirectory displays a directory picker according to the provided options.
+// It returns the path of the selected directory, a flag indicating whether the dialog
+// was canceled, and any error. If the dialog timed out, the error is ErrTimeout.
+func PromptDirectory(opts DirectoryDialogOptions) (path string, canceled bool, err error) {
+	return PromptDirectoryContext(context.Background(), opts)
+}
+
+// PromptDirectoryContext is like PromptDirectory, but closes the dialog when ctx is done.
+// In that case, the returned error is ctx.Err(), i.e. context.Canceled or context.DeadlineExceeded.
+func PromptDirectoryContext(ctx context.Context, opts DirectoryDialogOptions) (path string, canceled bool, err error) {
+	res, _ := RunDirectory(ctx, opts)
+	return promptResult(res, opts.TimeoutAction)
+}
+
+// RunDirectory displays a directory picker and closes it when ctx is done.
+// The path of the directory is returned in Result.Value.
+// The returned error is nil if the user confirmed the dialog, and ErrCanceled, ErrDismissed,
+// ErrTimeout or the cause of the failure otherwise.
+func RunDirectory(ctx context.Context, opts DirectoryDialogOptions) (Result, error) {
+	return run(ctx, newDirectoryDialog(opts))
+}
+
+func newDirectoryDialog(opts DirectoryDialogOptions) internaldialog.Dialog {
+	fsys, dir, root := fileSystem(opts.FS, opts.Root, opts.Dir)
+	dlg := internaldialog.NewDirectoryDialog(
+		opts.Width, opts.Height,
+		opts.Title, opts.Label, opts.Description, fsys, dir)
+	dlg.Root = root
+	dlg.ShowHidden = opts.ShowHidden
+	if opts.MustBeEmpty {
+		dlg.Validate = func(dir string) error { return checkEmpty(fsys, dir) }
+	}
+	if opts.MustBeWritable {
+		dlg.ValidateConfirm = func(dir string) error { return checkWritable(fsys, dir) }
+	}
+	if opts.Buttons != nil {
+		dlg.Buttons = opts.Buttons
+	}
+	dlg.Timeout, dlg.TimeoutAction = opts.Timeout, opts.TimeoutAction
+	return dlg
+}
+
+// checkEmpty checks that the directory dir of fsys has no entries.
+func checkEmpty(fsys fs.FS, dir string) error {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return err
+	}
+	if len(entries) > 0 {
+		return errors.New("the folder is not empty")
+	}
+	return nil
+}
+
+// checkWritable checks that files can be created in the directory dir of fsys. In the
+// real file system, it creates and removes a temporary file.
+func checkWritable(fsys fs.FS, dir string) error {
+	f, ok := fsys.(osFS)
+	if !ok {
+		if _, ok := fsys.(MkdirFS); !ok {
+			return errors.New("the folder is read-only")
+		}
+		return nil
+	}
+	tmp, err := os.CreateTemp(filepath.Join(f.root, filepath.FromSlash(dir)), ".write-check-*")
+	if err != nil {
+		return errors.New("the folder is not writable")
+	}
+	tmp.Close()
+	return os.Remove(tmp.Name())
+}
+
+// fileSystem returns the file system a file dialog browses and dir as a path of it.
+// Without fsys, that is the real file system, rooted at root or at the volume of dir,
+// which is returned as root.
+func fileSystem(fsys fs.FS, root, dir string) (fs.FS, string, string) {
+	if fsys != nil {
+		return fsys, cmp.Or(dir, "."), ""
+	}
+	abs, err := filepath.Abs(cmp.Or(dir, root, "."))
+	if err != nil {
+		abs = string(filepath.Separator)
+	}
+	if root != "" {
+		if root, err = filepath.Abs(root); err != nil {
+			root = abs
+		}
+	} else {
+		root = filepath.VolumeName(abs) + string(filepath.Separator)
+	}
+	// Directories outside of root fall back to root
+	rel, err := filepath.Rel(root, abs)
+	if err != nil || !filepath.IsLocal(rel) {
+		rel = "."
+	}
+	return osFS{os.DirFS(root), root}, filepath.ToSlash(rel), root
+}
+
+// osFS is the real file system below a root directory, in which directories can be created.
+type osFS struct {
+	fs.FS
+	root string
+}
+
+func (f osFS) Mkdir(name string, perm fs.FileMode) error {
+	if !fs.ValidPath(name) {
+		return &fs.PathError{Op: "mkdir", Path: name, Err: fs.ErrInvalid}
+	}
+	return os.Mkdir(filepath.Join(f.root, filepath.FromSlash(name)), perm)
+}
@@ -0,0 +1,367 @@
+package dialog
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+	"time"
+
+	internaldialog "github.com/gesellix/gioui-dialog/internal/dialog"
+)
+
+// FieldKind defines the input widget of a form field, and the type of its value.
+type FieldKind = internaldialog.FieldKind
+
+const (
+	// FieldText is a single line of text. Its value is a string.
+	FieldText = internaldialog.FieldText
+	// FieldPassword is a masked single line of text. Its value is a []byte.
+	FieldPassword = internaldialog.FieldPassword
+	// FieldNumber is a decimal number. Its value is a float64, or nil while the field is empty.
+	FieldNumber = internaldialog.FieldNumber
+	// FieldCheckbox is a checkbox. Its value is a bool.
+	FieldCheckbox = internaldialog.FieldCheckbox
+	// FieldCombo is one of Choices, picked from a list. Its value is a string.
+	FieldCombo = internaldialog.FieldCombo
+	// FieldDate is a date entered as YYYY-MM-DD. Its value is a time.Time in UTC,
+	// or nil while the field is empty.
+	FieldDate = internaldialog.FieldDate
+	// FieldMultiline is text spanning several lines. Its value is a string.
+	FieldMultiline = internaldialog.FieldMultiline
+)
+
+// FormField describes a field of a form dialog: the Name of its value in the result, its Label,
+// its Kind, its Default value, the Choices of a combo, and an optional Validate function,
+// whose error is shown below the field.
+type FormField = internaldialog.FormField
+
+// FormDialogOptions holds the configuration for a form dialog.
+type FormDialogOptions struct {
+	Width, Height float32 // Dimensions of the dialog window
+	Title         string  // Window title
+	Label         string  // Prompt label
+	Description   string  // Additional description or help text
+
+	// Fields are the fields of the form, from top to bottom.
+	Fields []FormField
+	// Validate is an optional validation function for the values of all fields, by name,
+	// e.g. to check that two fields fit together. It runs once every field is valid, and
+	// its error is shown below the fields.
+	Validate func(values map[string]any) error
+
+	// Buttons replace the default buttons, from left to right (optional).
+	Buttons []Button
+
+	// Timeout closes the dialog automatically after the given duration (optional).
+	// The remaining seconds are shown on the button that TimeoutAction triggers,
+	// and the countdown stops as soon as the user interacts with the dialog.
+	Timeout       time.Duration
+	TimeoutAction TimeoutAction // How the dialog is closed when Timeout expires
+}
+
+// PromptForm displays a form dialog according to the provided options.
+// It returns the values of the fields by name, a flag indicating whether the dialog was
+// canceled, and any error. If the dialog timed out, the error is ErrTimeout.
+func PromptForm(opts FormDialogOptions) (values map[string]any, canceled bool, err error) {
+	return PromptFormContext(context.Background(), opts)
+}
+
+// PromptFormContext is like PromptForm, but closes the dialog when ctx is done.
+// In that case, the returned error is ctx.Err(), i.e. context.Canceled or context.DeadlineExceeded.
+func PromptFormContext(ctx context.Context, opts FormDialogOptions) (values map[string]any, canceled bool, err error) {
+	res, _ := RunForm(ctx, opts)
+	_, canceled, err = promptResult(res, opts.TimeoutAction)
+	if res.Outcome == Canceled || res.Outcome == Error {
+		return nil, canceled, err
+	}
+	return res.Fields, canceled, err
+}
+
+// RunForm displays a form dialog and closes it when ctx is done.
+// The values of the fields are returned in Result.Fields.
+// The returned error is nil if the user confirmed the dialog, and ErrCanceled, ErrDismissed,
+// ErrTimeout or the cause of the failure otherwise.
+func RunForm(ctx context.Context, opts FormDialogOptions) (Result, error) {
+	return run(ctx, newFormDialog(opts))
+}
+
+func newFormDialog(opts FormDialogOptions) internaldialog.Dialog {
+	dlg := internaldialog.NewFormDialog(
+		opts.Width, opts.Height,
+		opts.Title, opts.Label, opts.Description, opts.Fields)
+	dlg.Validate = opts.Validate
+	dlg.Buttons = opts.Buttons
+	dlg.Timeout, dlg.TimeoutAction = opts.Timeout, opts.TimeoutAction
+	return dlg
+}
+
+// PromptFormStruct displays a form dialog for the struct v points to, and stores the values
+// in it once the user confirmed the dialog. Without opts.Fields, the fields are derived from
+// the struct, see FormFieldsOf; otherwise, their names have to match the struct fields.
+// It returns a flag indicating whether the dialog was canceled, and any error.
+// If the dialog timed out, the error is ErrTimeout.
+func PromptFormStruct(opts FormDialogOptions, v any) (canceled bool, err error) {
+	return PromptFormStructContext(context.Background(), opts, v)
+}
+
+// PromptFormStructContext is like PromptFormStruct, but closes the dialog when ctx is done.
+// In that case, the returned error is ctx.Err(), i.e. context.Canceled or context.DeadlineExceeded.
+func PromptFormStructContext(ctx context.Context, opts FormDialogOptions, v any) (canceled bool, err error) {
+	if opts.Fields == nil {
+		if opts.Fields, err = FormFieldsOf(v); err != nil {
+			return false, err
+		}
+	}
+	values, canceled, err := PromptFormContext(ctx, opts)
+	if values == nil {
+		return canceled, err
+	}
+	if fillErr := fillStruct(v, values); fillErr != nil {
+		return canceled, fillErr
+	}
+	return canceled, err
+}
+
+// FormFieldsOf returns a form field for every exported field of the struct v points to,
+// with the current value as default. The `form` struct tag configures a field:
+//
+//	Host     string    `form:"host,label=Server host"`
+//	Password []byte    `form:"password"`
+//	Level    string    `form:"level,choices=low|medium|high"`
+//	Notes    string    `form:"notes,kind=multiline"`
+//	Internal string    `form:"-"`
+//
+// The name defaults to the name of the struct field, and so does the label. The kind follows
+// from the type: strings are text, []byte are passwords, numbers are numbers, bools are
+// checkboxes, time.Time are dates, and fields with choices are combos. The kind option
+// overrides it: text, password, number, checkbox, combo, date or multiline. Integer fields
+// only accept whole numbers that fit.
+func FormFieldsOf(v any) ([]FormField, error) {
+	rv, err := structValue(v)
+	if err != nil {
+		return nil, err
+	}
+	var fields []FormField
+	for i := range rv.NumField() {
+		sf := rv.Type().Field(i)
+		tag := sf.Tag.Get("form")
+		if !sf.IsExported() || tag == "-" {
+			continue
+		}
+		name, options, _ := strings.Cut(tag, ",")
+		f := FormField{
+			Name:    cmp.Or(name, sf.Name),
+			Label:   sf.Name,
+			Default: rv.Field(i).Interface(),
+		}
+		kind, ok := kindOf(sf.Type)
+		for option := range strings.SplitSeq(options, ",") {
+			key, value, _ := strings.Cut(option, "=")
+			switch key {
+			case "":
+			case "label":
+				f.Label = value
+			case "choices":
+				f.Choices = strings.Split(value, "|")
+				kind, ok = FieldCombo, sf.Type.Kind() == reflect.String
+			case "kind":
+				if kind, ok = fieldKinds[value]; !ok {
+					return nil, fmt.Errorf("dialog: unknown kind %q of field %s", value, sf.Name)
+				}
+			default:
+				return nil, fmt.Errorf("dialog: unknown option %q of field %s", key, sf.Name)
+			}
+		}
+		if !ok || !fits(kind, sf.Type) {
+			return nil, fmt.Errorf("dialog: unsupported type %s of field %s", sf.Type, sf.Name)
+		}
+		f.Kind = kind
+		// Named string and bool types, e.g. `type Level string`, have the default of the underlying type
+		switch fv := rv.Field(i); fv.Kind() {
+		case reflect.String:
+			f.Default = fv.String()
+			if kind == FieldCombo && fv.String() == "" {
+				f.Default = nil
+			}
+		case reflect.Bool:
+			f.Default = fv.Bool()
+		}
+		if kind == FieldNumber {
+			// Reject numbers that the struct field can't hold
+			f.Validate = func(value any) error {
+				return setField(reflect.New(sf.Type).Elem(), value)
+			}
+		}
+		fields = append(fields, f)
+	}
+	return fields, nil
+}
+
+// fieldKinds maps the kind option of the form struct tag to the kinds of fields.
+var fieldKinds = map[string]FieldKind{
+	"text":      FieldText,
+	"password":  FieldPassword,
+	"number":    FieldNumber,
+	"checkbox":  FieldCheckbox,
+	"combo":     FieldCombo,
+	"date":      FieldDate,
+	"multiline": FieldMultiline,
+}
+
+var (
+	bytesType = reflect.TypeFor[[]byte]()
+	timeType  = reflect.TypeFor[time.Time]()
+)
+
+// kindOf returns the kind of field for a struct field of type t.
+func kindOf(t reflect.Type) (FieldKind, bool) {
+	switch {
+	case t == bytesType:
+		return FieldPassword, true
+	case t == timeType:
+		return FieldDate, true
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return FieldText, true
+	case reflect.Bool:
+		return FieldCheckbox, true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return FieldNumber, true
+	}
+	return 0, false
+}
+
+// fits reports whether a struct field of type t can hold the values of a field of the given kind.
+func fits(kind FieldKind, t reflect.Type) bool {
+	switch kind {
+	case FieldPassword:
+		return t == bytesType || t.Kind() == reflect.String
+	case FieldText, FieldCombo, FieldMultiline:
+		return t.Kind() == reflect.String
+	default:
+		k, ok := kindOf(t)
+		return ok && k == kind
+	}
+}
+
+// structValue returns the struct v points to.
+func structValue(v any) (reflect.Value, error) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return reflect.Value{}, fmt.Errorf("dialog: %T is not a pointer to a struct", v)
+	}
+	return rv.Elem(), nil
+}
+
+// fillStruct stores the values of a form in the fields of the struct v points to.
+func fillStruct(v any, values map[string]any) error {
+	rv, err := structValue(v)
+	if err != nil {
+		return err
+	}
+	for i := range rv.NumField() {
+		sf := rv.Type().Field(i)
+		tag := sf.Tag.Get("form")
+		if !sf.IsExported() || tag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+		value, ok := values[cmp.Or(name, sf.Name)]
+		if !ok {
+			continue
+		}
+		if err := setField(rv.Field(i), value); err != nil {
+			return fmt.Errorf("dialog: field %s: %w", sf.Name, err)
+		}
+	}
+	return nil
+}
+
+// setField stores the value of a form field in the struct field fv. Empty numbers and dates
+// reset it.
+func setField(fv reflect.Value, value any) error {
+	switch value := value.(type) {
+	case nil:
+		fv.SetZero()
+		return nil
+	case string:
+		if fv.Kind() == reflect.String {
+			fv.SetString(value)
+			return nil
+		}
+	case []byte:
+		switch {
+		case fv.Type() == bytesType:
+			fv.SetBytes(value)
+			return nil
+		case fv.Kind() == reflect.String:
+			fv.SetString(string(value))
+			return nil
+		}
+	case bool:
+		if fv.Kind() == reflect.Bool {
+			fv.SetBool(value)
+			return nil
+		}
+	case time.Time:
+		if fv.Type() == timeType {
+			fv.Set(reflect.ValueOf(value))
+			return nil
+		}
+	case float64:
+		return setNumber(fv, value)
+	}
+	return fmt.Errorf("can't store %T in %s", value, fv.Type())
+}
+
+// errOutOfRange is returned for numbers that a struct field can't hold.
+var errOutOfRange = errors.New("the number is out of range")
+
+// setNumber stores n in the numeric field fv.
+func setNumber(fv reflect.Value, n float64) error {
+	switch fv.Kind() {
+	case reflect.Float32, reflect.Float64:
+		if fv.OverflowFloat(n) {
+			return errOutOfRange
+		}
+		fv.SetFloat(n)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		// Converting a float64 beyond the range of int64 is undefined.
+		if n < math.MinInt64 || n >= math.MaxInt64 {
+			return errOutOfRange
+		}
+		i := int64(n)
+		if float64(i) != n {
+			return errors.New("enter a whole number")
+		}
+		if fv.OverflowInt(i) {
+			return errOutOfRange
+		}
+		fv.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if n < 0 {
+			return errors.New("enter a whole number of at least 0")
+		}
+		if n >= math.MaxUint64 {
+			return errOutOfRange
+		}
+		u := uint64(n)
+		if float64(u) != n {
+			return errors.New("enter a whole number of at least 0")
+		}
+		if fv.OverflowUint(u) {
+			return errOutOfRange
+		}
+		fv.SetUint(u)
+	default:
+		return fmt.Errorf("can't store a number in %s", fv.Type())
+	}
+	return nil
+}
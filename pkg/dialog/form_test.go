@@ -0,0 +1,200 @@
+package dialog
+
+import (
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type Level string
+
+func TestFormFieldsOf(t *testing.T) {
+	v := struct {
+		Host     string `form:"host,label=Server host"`
+		Password []byte `form:"password"`
+		PIN      string `form:",kind=password"`
+		Level    Level  `form:"level,choices=low|high"`
+		Notes    string `form:"notes,kind=multiline"`
+		Port     uint16
+		Remember bool
+		Due      time.Time
+		Internal string `form:"-"`
+		secret   string
+	}{Host: "example.org", Password: []byte("s3cret"), Port: 22, Remember: true}
+
+	fields, err := FormFieldsOf(&v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []FormField{
+		{Name: "host", Label: "Server host", Kind: FieldText, Default: "example.org"},
+		{Name: "password", Label: "Password", Kind: FieldPassword, Default: []byte("s3cret")},
+		{Name: "PIN", Label: "PIN", Kind: FieldPassword, Default: ""},
+		{Name: "level", Label: "Level", Kind: FieldCombo, Choices: []string{"low", "high"}},
+		{Name: "notes", Label: "Notes", Kind: FieldMultiline, Default: ""},
+		{Name: "Port", Label: "Port", Kind: FieldNumber, Default: uint16(22)},
+		{Name: "Remember", Label: "Remember", Kind: FieldCheckbox, Default: true},
+		{Name: "Due", Label: "Due", Kind: FieldDate, Default: time.Time{}},
+	}
+	// Number fields only accept values that the struct field can hold.
+	port := fields[5].Validate
+	if port == nil || port(22.0) != nil || port(70000.0) == nil || port(-1.0) == nil {
+		t.Error("the port accepts numbers out of the range of uint16")
+	}
+	for i := range fields {
+		if fields[i].Kind != FieldNumber && fields[i].Validate != nil {
+			t.Errorf("field %s has a validation", fields[i].Name)
+		}
+		// Functions aren't comparable
+		fields[i].Validate = nil
+	}
+	if !reflect.DeepEqual(fields, want) {
+		t.Errorf("got %+v, want %+v", fields, want)
+	}
+}
+
+func TestFormFieldsOfErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		v    any
+		want string
+	}{
+		{"not a pointer", struct{}{}, "is not a pointer to a struct"},
+		{"nil", (*struct{})(nil), "is not a pointer to a struct"},
+		{"unknown option", &struct {
+			A string `form:"a,placeholder=x"`
+		}{}, `unknown option "placeholder" of field A`},
+		{"unknown kind", &struct {
+			A string `form:"a,kind=slider"`
+		}{}, `unknown kind "slider" of field A`},
+		{"choices of a number", &struct {
+			A int `form:"a,choices=1|2"`
+		}{}, "unsupported type int of field A"},
+		{"choices of bytes", &struct {
+			A []byte `form:"a,choices=x|y"`
+		}{}, "unsupported type []uint8 of field A"},
+		{"kind of another type", &struct {
+			A bool `form:"a,kind=text"`
+		}{}, "unsupported type bool of field A"},
+		{"unsupported type", &struct {
+			A []string
+		}{}, "unsupported type []string of field A"},
+		{"unsupported kind", &struct {
+			A complex128
+		}{}, "unsupported type complex128 of field A"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := FormFieldsOf(tt.v)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("got %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestFillStruct(t *testing.T) {
+	type form struct {
+		Host     string `form:"host"`
+		Password []byte `form:"password"`
+		PIN      string `form:"pin,kind=password"`
+		Level    Level  `form:"level,choices=low|high"`
+		Port     uint16
+		Ratio    float32
+		Remember bool
+		Due      time.Time
+		Internal string `form:"-"`
+	}
+	v := form{Port: 22, Due: time.Now(), Internal: "kept"}
+	due := time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC)
+	err := fillStruct(&v, map[string]any{
+		"host":     "example.org",
+		"password": []byte("s3cret"),
+		"pin":      []byte("1234"),
+		"level":    "high",
+		"Port":     nil,
+		"Ratio":    0.5,
+		"Remember": true,
+		"Due":      due,
+		"Internal": "changed",
+		"unknown":  "ignored",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := form{Host: "example.org", Password: []byte("s3cret"), PIN: "1234", Level: "high", Ratio: 0.5, Remember: true, Due: due, Internal: "kept"}
+	if !reflect.DeepEqual(v, want) {
+		t.Errorf("got %+v, want %+v", v, want)
+	}
+}
+
+func TestFillStructErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		values map[string]any
+		want   string
+	}{
+		{"text in a number", map[string]any{"Port": "22"}, "field Port: can't store string in uint16"},
+		{"number in a text", map[string]any{"Host": 22.0}, "field Host: can't store a number in string"},
+		{"bool in a date", map[string]any{"Due": true}, "field Due: can't store bool in time.Time"},
+		{"fraction", map[string]any{"Port": 22.5}, "field Port: enter a whole number of at least 0"},
+		{"overflow", map[string]any{"Port": 70000.0}, "field Port: the number is out of range"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var v struct {
+				Host string
+				Port uint16
+				Due  time.Time
+			}
+			if err := fillStruct(&v, tt.values); err == nil || err.Error() != "dialog: "+tt.want {
+				t.Errorf("got %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetNumber(t *testing.T) {
+	tests := []struct {
+		ptr  any // points to the field
+		n    float64
+		want any // the value of the field, or the error
+	}{
+		{new(int), 42, 42},
+		{new(int), -42, -42},
+		{new(int), 1.5, "enter a whole number"},
+		{new(int), math.NaN(), "enter a whole number"},
+		{new(int8), 127, int8(127)},
+		{new(int8), 128, "the number is out of range"},
+		{new(int64), -1 << 63, int64(-1 << 63)},
+		{new(int64), 1 << 63, "the number is out of range"},
+		{new(int64), 1e30, "the number is out of range"},
+		{new(int64), math.Inf(-1), "the number is out of range"},
+		{new(uint), 0, uint(0)},
+		{new(uint), -1, "enter a whole number of at least 0"},
+		{new(uint), 0.5, "enter a whole number of at least 0"},
+		{new(uint8), 256, "the number is out of range"},
+		{new(uint64), 1 << 64, "the number is out of range"},
+		{new(uint64), math.Inf(1), "the number is out of range"},
+		{new(float32), 0.25, float32(0.25)},
+		{new(float32), -math.MaxFloat32, float32(-math.MaxFloat32)},
+		{new(float32), 1e39, "the number is out of range"},
+		{new(float64), 1e30, 1e30},
+		{new(string), 1, "can't store a number in string"},
+	}
+	for _, tt := range tests {
+		fv := reflect.ValueOf(tt.ptr).Elem()
+		err := setNumber(fv, tt.n)
+		if want, ok := tt.want.(string); ok {
+			if err == nil || err.Error() != want {
+				t.Errorf("setNumber(%s, %v) = %v, want %q", fv.Type(), tt.n, err, want)
+			}
+			continue
+		}
+		if err != nil || fv.Interface() != tt.want {
+			t.Errorf("setNumber(%s, %v) = %v, %v, want %v", fv.Type(), tt.n, fv.Interface(), err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package dialog
+
+import (
+	"gioui.org/layout"
+	"gioui.org/widget/material"
+
+	internaldialog "github.com/gesellix/gioui-dialog/internal/dialog"
+)
+
+// Modal is a dialog that is drawn as an overlay inside an existing Gio window,
+// instead of opening a window of its own.
+//
+// Call Layout on every frame after laying out the rest of the window, so that
+// the dialog and its scrim are drawn on top. Once the user closes the dialog,
+// the result is sent to the Result channel and passed to OnResult, and Layout
+// stops drawing anything.
+type Modal struct {
+	// OnResult is called from Layout when the user closes the dialog (optional).
+	OnResult func(Result)
+	// Invalidate requests a new frame of the window the dialog is drawn in,
+	// usually app.Window.Invalidate. Dialogs that change without user input,
+	// such as asynchronous validation or progress updates, need it to be
+	// redrawn (optional). It is called from other goroutines.
+	Invalidate func()
+
+	modal   *internaldialog.Modal
+	results chan Result
+}
+
+// NewInputModal creates an in-window text-input dialog according to the provided options.
+// Width and Height define the size of the dialog within the window.
+func NewInputModal(opts InputDialogOptions) *Modal {
+	return newModal(newInputDialog(opts))
+}
+
+// NewPasswordModal creates an in-window password dialog according to the provided options.
+// Width and Height define the size of the dialog within the window.
+func NewPasswordModal(opts PasswordDialogOptions) *Modal {
+	return newModal(newPasswordDialog(opts))
+}
+
+// NewSelectModal creates an in-window single-select dialog according to the provided options.
+// Width and Height define the size of the dialog within the window.
+func NewSelectModal(opts SelectDialogOptions) *Modal {
+	return newModal(newSelectDialog(opts))
+}
+
+// NewMultiSelectModal creates an in-window multi-select dialog according to the provided options.
+// Width and Height define the size of the dialog within the window.
+func NewMultiSelectModal(opts MultiSelectDialogOptions) *Modal {
+	return newModal(newMultiSelectDialog(opts))
+}
+
+// NewBaseModal creates an in-window base dialog according to the provided options.
+// Width and Height define the size of the dialog within the window.
+func NewBaseModal(opts BaseDialogOptions) *Modal {
+	return newModal(newBaseDialog(opts))
+}
+
+// NewMessageModal creates an in-window message dialog of the given severity according to the provided options.
+// Width and Height define the size of the dialog within the window.
+func NewMessageModal(severity Severity, opts MessageDialogOptions) *Modal {
+	return newModal(newMessageDialog(severity, opts))
+}
+
+// NewOpenFileModal creates an in-window file open dialog according to the provided options.
+// Width and Height define the size of the dialog within the window.
+func NewOpenFileModal(opts OpenFileDialogOptions) *Modal {
+	return newModal(newOpenFileDialog(opts))
+}
+
+// NewSaveFileModal creates an in-window save file dialog according to the provided options.
+// Width and Height define the size of the dialog within the window.
+func NewSaveFileModal(opts SaveFileDialogOptions) *Modal {
+	return newModal(newSaveFileDialog(opts))
+}
+
+// NewDirectoryModal creates an in-window directory picker according to the provided options.
+// Width and Height define the size of the dialog within the window.
+func NewDirectoryModal(opts DirectoryDialogOptions) *Modal {
+	return newModal(newDirectoryDialog(opts))
+}
+
+// NewFormModal creates an in-window form dialog according to the provided options.
+// Width and Height define the size of the dialog within the window.
+func NewFormModal(opts FormDialogOptions) *Modal {
+	return newModal(newFormDialog(opts))
+}
+
+func newModal(d internaldialog.Dialog) *Modal {
+	m := &Modal{results: make(chan Result, 1)}
+	m.modal = internaldialog.NewModal(d, m.invalidate)
+	return m
+}
+
+// invalidate requests a new frame through Invalidate, if it is set.
+func (m *Modal) invalidate() {
+	if m.Invalidate != nil {
+		m.Invalidate()
+	}
+}
+
+// Layout draws the dialog on top of a scrim covering the space available in gtx.
+func (m *Modal) Layout(gtx layout.Context, th *material.Theme) layout.Dimensions {
+	dims, closed := m.modal.Layout(gtx, th)
+	if closed {
+		res := newResult(m.modal.Result())
+		m.results <- res
+		if m.OnResult != nil {
+			m.OnResult(res)
+		}
+	}
+	return dims
+}
+
+// Visible reports whether the dialog is still open.
+func (m *Modal) Visible() bool {
+	return !m.modal.Closed()
+}
+
+// Result returns a channel that receives the result once the user closed the dialog.
+func (m *Modal) Result() <-chan Result {
+	return m.results
+}
@@ -0,0 +1,119 @@
+package dialog
+
+import (
+	"context"
+	"time"
+
+	internaldialog "github.com/gesellix/gioui-dialog/internal/dialog"
+)
+
+// ProgressDialogOptions holds the configuration for a progress dialog.
+type ProgressDialogOptions struct {
+	Width, Height float32 // Dimensions of the dialog window
+	Title         string  // Window title
+	Label         string  // Prompt label
+	Description   string  // Additional description or help text
+	Text          string  // Initial status text below the label
+	Percent       float64 // Initial progress, between 0 and 100
+	Pulsate       bool    // Show a pulsating bar until the first SetPercent, for work of unknown duration
+	AutoClose     bool    // Close the dialog as soon as the work is complete
+
+	// Buttons replace the default Cancel and OK buttons, from left to right (optional).
+	// Affirmative buttons are enabled once the work is complete.
+	Buttons []Button
+
+	// Timeout closes the dialog automatically after the given duration (optional).
+	// The remaining seconds are shown on the button that TimeoutAction triggers,
+	// and the countdown stops as soon as the user interacts with the dialog.
+	Timeout       time.Duration
+	TimeoutAction TimeoutAction // How the dialog is closed when Timeout expires
+}
+
+// progressDialog is implemented by the internal progress dialog.
+type progressDialog interface {
+	internaldialog.Dialog
+	SetPercent(percent float64)
+	SetText(text string)
+	SetPulsating(pulsating bool)
+	Complete()
+	Close()
+}
+
+// Progress controls a progress dialog shown by StartProgress.
+// Its methods may be called from any goroutine.
+type Progress struct {
+	dlg  progressDialog
+	done chan struct{}
+	res  Result
+	err  error
+}
+
+// StartProgress displays a progress dialog according to the provided options and returns
+// immediately. The caller reports the progress of its work through the returned Progress.
+//
+// The returned context is derived from ctx and canceled once the dialog is closed, in
+// particular when the user presses Cancel. Its cause, see context.Cause, is then the error
+// Wait returns, e.g. ErrCanceled. The dialog closes when ctx is done.
+func StartProgress(ctx context.Context, opts ProgressDialogOptions) (*Progress, context.Context) {
+	ctx, cancel := context.WithCancelCause(ctx)
+	p := &Progress{dlg: newProgressDialog(opts), done: make(chan struct{})}
+	go func() {
+		defer close(p.done)
+		p.res, p.err = run(ctx, p.dlg)
+		cancel(p.err)
+	}()
+	return p, ctx
+}
+
+func newProgressDialog(opts ProgressDialogOptions) progressDialog {
+	dlg := internaldialog.NewProgressDialog(
+		opts.Width, opts.Height,
+		opts.Title, opts.Label, opts.Description, opts.Text)
+	dlg.SetPercent(opts.Percent)
+	dlg.SetPulsating(opts.Pulsate)
+	dlg.AutoClose = opts.AutoClose
+	dlg.Buttons = opts.Buttons
+	dlg.Timeout, dlg.TimeoutAction = opts.Timeout, opts.TimeoutAction
+	return dlg
+}
+
+// SetPercent sets the progress, between 0 and 100, and stops pulsating.
+// Reaching 100 completes the work, see Complete.
+func (p *Progress) SetPercent(percent float64) {
+	p.dlg.SetPercent(percent)
+}
+
+// SetText sets the status text below the label.
+func (p *Progress) SetText(text string) {
+	p.dlg.SetText(text)
+}
+
+// SetPulsating switches between a bar showing the progress and a pulsating one,
+// for work of unknown duration.
+func (p *Progress) SetPulsating(pulsating bool) {
+	p.dlg.SetPulsating(pulsating)
+}
+
+// Complete marks the work as done: the bar is filled, and the user can close the dialog
+// with OK. With AutoClose, the dialog closes right away.
+func (p *Progress) Complete() {
+	p.dlg.Complete()
+}
+
+// Close completes the work and closes the dialog.
+func (p *Progress) Close() {
+	p.dlg.Close()
+}
+
+// Done returns a channel that is closed once the dialog was closed.
+func (p *Progress) Done() <-chan struct{} {
+	return p.done
+}
+
+// Wait waits until the dialog was closed and returns its Result.
+// The returned error is nil if the dialog was closed with OK, by Close or by AutoClose,
+// and ErrCanceled, ErrDismissed, ErrTimeout or the cause of the failure otherwise.
+func (p *Progress) Wait() (Result, error) {
+	<-p.done
+	return p.res, p.err
+}
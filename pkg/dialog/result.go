@@ -0,0 +1,88 @@
+package dialog
+
+import (
+	internaldialog "github.com/gesellix/gioui-dialog/internal/dialog"
+)
+
+var (
+	// ErrCanceled is returned when the user canceled a dialog.
+	ErrCanceled = internaldialog.ErrCanceled
+	// ErrDismissed is returned when a dialog was closed without an answer, e.g. through the window manager.
+	ErrDismissed = internaldialog.ErrDismissed
+	// ErrTimeout is returned when a dialog was closed because its Timeout expired.
+	// The other results then reflect the TimeoutAction that was applied.
+	ErrTimeout = internaldialog.ErrTimeout
+)
+
+// Outcome describes how a dialog was closed.
+type Outcome = internaldialog.Outcome
+
+const (
+	// Dismissed means the dialog was closed without an answer, e.g. through the window manager.
+	Dismissed = internaldialog.Dismissed
+	// Confirmed means the user confirmed the dialog.
+	Confirmed = internaldialog.Confirmed
+	// Canceled means the user canceled the dialog.
+	Canceled = internaldialog.Canceled
+	// TimedOut means the dialog was closed because its Timeout expired.
+	TimedOut = internaldialog.TimedOut
+	// Error means the dialog failed, or its context was done.
+	Error = internaldialog.Error
+)
+
+// Result holds how a dialog was closed and the value it produced.
+type Result struct {
+	Outcome Outcome  // How the dialog was closed
+	Value   string   // Entered text or selected item; also set when a timeout confirmed the default
+	Values  []string // Checked items of a multi-select dialog; also set when a timeout confirmed the defaults
+	Secret  []byte   // Password entered in a password dialog; zero it after use
+	Button  Button   // Button that closed the dialog; zero if it was closed otherwise
+	Err     error    // Cause of the Error outcome
+
+	// Fields are the values of the fields of a form dialog, by name.
+	Fields map[string]any
+}
+
+func newResult(r internaldialog.Result) Result {
+	return Result{
+		Outcome: r.Outcome,
+		Value:   r.Value,
+		Values:  r.Values,
+		Secret:  r.Secret,
+		Button:  r.Button,
+		Err:     r.Err,
+		Fields:  r.Fields,
+	}
+}
+
+// asError returns nil for confirmed dialogs, and the sentinel error or cause
+// matching the outcome otherwise.
+func (r Result) asError() error {
+	switch r.Outcome {
+	case Confirmed:
+		return nil
+	case Canceled:
+		return ErrCanceled
+	case TimedOut:
+		return ErrTimeout
+	case Error:
+		return r.Err
+	default:
+		return ErrDismissed
+	}
+}
+
+// promptResult converts a Result to the return values of the Prompt functions,
+// which don't distinguish dismissed from confirmed dialogs.
+func promptResult(r Result, action TimeoutAction) (value string, canceled bool, err error) {
+	switch r.Outcome {
+	case Canceled:
+		return "", true, nil
+	case TimedOut:
+		return r.Value, action == TimeoutCancel, ErrTimeout
+	case Error:
+		return "", false, r.Err
+	default:
+		return r.Value, false, nil
+	}
+}
@@ -0,0 +1,449 @@
+package dialog
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// SpecSchema is the JSON Schema of the dialog specs read by LoadSpec, e.g. for the
+// completion and validation of spec files in editors.
+//
+//go:embed spec.schema.json
+var SpecSchema string
+
+// Spec describes a dialog as data, e.g. loaded from a JSON or YAML file with LoadSpec, so
+// that its texts can be changed without recompiling. Type selects the dialog, and the other
+// fields map onto the options of that dialog type; fields that don't apply to the type are
+// rejected by Validate.
+type Spec struct {
+	// Type is input, password, select, multiselect, form, info, warning, error or question.
+	Type string `json:"type" yaml:"type"`
+
+	Width       float32 `json:"width,omitempty" yaml:"width,omitempty"`
+	Height      float32 `json:"height,omitempty" yaml:"height,omitempty"`
+	Title       string  `json:"title,omitempty" yaml:"title,omitempty"`
+	Label       string  `json:"label,omitempty" yaml:"label,omitempty"`
+	Description string  `json:"description,omitempty" yaml:"description,omitempty"`
+	// Message is the text of a message dialog.
+	Message string `json:"message,omitempty" yaml:"message,omitempty"`
+
+	// Default is the initial text of an input dialog, or the selected choice of a select dialog.
+	Default string `json:"default,omitempty" yaml:"default,omitempty"`
+	// Defaults are the checked choices of a multiselect dialog.
+	Defaults []string `json:"defaults,omitempty" yaml:"defaults,omitempty"`
+	// Choices are the options of a select or multiselect dialog.
+	Choices          []string `json:"choices,omitempty" yaml:"choices,omitempty"`
+	AllowCustomEntry bool     `json:"allow_custom_entry,omitempty" yaml:"allow_custom_entry,omitempty"`
+	Searchable       bool     `json:"searchable,omitempty" yaml:"searchable,omitempty"`
+	MinSelections    int      `json:"min_selections,omitempty" yaml:"min_selections,omitempty"`
+	MaxSelections    int      `json:"max_selections,omitempty" yaml:"max_selections,omitempty"`
+	// Search is how the search field of a select dialog matches the choices: substring (the
+	// default) or fuzzy. Setting it shows the search field, like Searchable.
+	Search string `json:"search,omitempty" yaml:"search,omitempty"`
+	// Confirmation asks for the password of a password dialog twice.
+	Confirmation bool `json:"confirmation,omitempty" yaml:"confirmation,omitempty"`
+
+	// Validators names the validators of the text of an input dialog, which run in order;
+	// see RegisterValidator.
+	Validators []string `json:"validate,omitempty" yaml:"validate,omitempty"`
+	// Pattern is a regular expression that the whole text of an input dialog has to match.
+	Pattern string `json:"pattern,omitempty" yaml:"pattern,omitempty"`
+	// PatternMessage is the error shown when the text doesn't match Pattern.
+	PatternMessage string `json:"pattern_message,omitempty" yaml:"pattern_message,omitempty"`
+
+	// Fields are the fields of a form dialog, from top to bottom.
+	Fields []FieldSpec `json:"fields,omitempty" yaml:"fields,omitempty"`
+
+	// Buttons replace the default buttons, from left to right.
+	Buttons []ButtonSpec `json:"buttons,omitempty" yaml:"buttons,omitempty"`
+
+	// Timeout is a duration like "30s" after which the dialog is closed.
+	Timeout string `json:"timeout,omitempty" yaml:"timeout,omitempty"`
+	// TimeoutAction is cancel (the default) or confirm.
+	TimeoutAction string `json:"timeout_action,omitempty" yaml:"timeout_action,omitempty"`
+}
+
+// FieldSpec describes a field of a form dialog in a Spec.
+type FieldSpec struct {
+	Name  string `json:"name" yaml:"name"`
+	Label string `json:"label,omitempty" yaml:"label,omitempty"`
+	// Kind is text (the default), password, number, checkbox, combo, date or multiline.
+	Kind string `json:"kind,omitempty" yaml:"kind,omitempty"`
+	// Default is the initial value: a bool for checkboxes, and a string or a number otherwise.
+	Default any      `json:"default,omitempty" yaml:"default,omitempty"`
+	Choices []string `json:"choices,omitempty" yaml:"choices,omitempty"`
+
+	// Validators, Pattern and PatternMessage validate the text of the field, like the
+	// ones of Spec. Empty number and date fields have the text "".
+	Validators     []string `json:"validate,omitempty" yaml:"validate,omitempty"`
+	Pattern        string   `json:"pattern,omitempty" yaml:"pattern,omitempty"`
+	PatternMessage string   `json:"pattern_message,omitempty" yaml:"pattern_message,omitempty"`
+}
+
+// ButtonSpec describes a dialog button in a Spec.
+type ButtonSpec struct {
+	Label string `json:"label" yaml:"label"`
+	// Role is affirmative, negative, neutral or destructive.
+	Role    string `json:"role" yaml:"role"`
+	Default bool   `json:"default,omitempty" yaml:"default,omitempty"`
+	Cancel  bool   `json:"cancel,omitempty" yaml:"cancel,omitempty"`
+}
+
+var (
+	validatorsMu sync.RWMutex
+	validators   = map[string]func(string) error{
+		"required": func(s string) error {
+			if strings.TrimSpace(s) == "" {
+				return errors.New("enter a value")
+			}
+			return nil
+		},
+		"number": func(s string) error {
+			if _, err := strconv.ParseFloat(strings.TrimSpace(s), 64); s != "" && err != nil {
+				return errors.New("enter a number")
+			}
+			return nil
+		},
+		"integer": func(s string) error {
+			if _, err := strconv.Atoi(strings.TrimSpace(s)); s != "" && err != nil {
+				return errors.New("enter a whole number")
+			}
+			return nil
+		},
+		"email": func(s string) error {
+			local, domain, ok := strings.Cut(s, "@")
+			if s != "" && (!ok || local == "" || !strings.Contains(domain, ".")) {
+				return errors.New("enter an email address")
+			}
+			return nil
+		},
+	}
+)
+
+// RegisterValidator makes a validation function available to specs under the given name,
+// replacing any validator registered before under that name. The built-in validators are
+// required, number, integer and email; all of them but required accept an empty text.
+func RegisterValidator(name string, validate func(string) error) {
+	validatorsMu.Lock()
+	defer validatorsMu.Unlock()
+	validators[name] = validate
+}
+
+func lookupValidator(name string) (func(string) error, bool) {
+	validatorsMu.RLock()
+	defer validatorsMu.RUnlock()
+	v, ok := validators[name]
+	return v, ok
+}
+
+// LoadSpec reads a dialog spec in JSON or YAML from r and validates it. Keys that aren't
+// part of the spec are rejected, to catch typos.
+func LoadSpec(r io.Reader) (*Spec, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	var spec Spec
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '{' {
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		err = dec.Decode(&spec)
+	} else {
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		err = dec.Decode(&spec)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("parsing the dialog spec: %w", err)
+	}
+	if err := spec.Validate(); err != nil {
+		return nil, err
+	}
+	return &spec, nil
+}
+
+var (
+	specRoles = map[string]ButtonRole{
+		"affirmative": RoleAffirmative,
+		"negative":    RoleNegative,
+		"neutral":     RoleNeutral,
+		"destructive": RoleDestructive,
+	}
+	specSeverities = map[string]Severity{
+		"info":     SeverityInfo,
+		"warning":  SeverityWarning,
+		"error":    SeverityError,
+		"question": SeverityQuestion,
+	}
+)
+
+// Validate checks that s describes a dialog that can be shown, and returns all problems it
+// found, each prefixed with the key it concerns.
+func (s *Spec) Validate() error {
+	var errs []error
+	fail := func(key, format string, args ...any) {
+		errs = append(errs, fmt.Errorf(key+": "+format, args...))
+	}
+	// only reports the keys that are set although they don't apply to the type
+	only := func(types string, set bool, key string) {
+		if set && !slices.Contains(strings.Fields(types), s.Type) {
+			fail(key, "not supported by %s dialogs", s.Type)
+		}
+	}
+
+	switch s.Type {
+	case "input", "password", "select", "multiselect", "form", "info", "warning", "error", "question":
+	case "":
+		fail("type", "missing")
+	default:
+		fail("type", "unknown dialog type %q", s.Type)
+	}
+	only("info warning error question", s.Message != "", "message")
+	only("input select", s.Default != "", "default")
+	only("multiselect", len(s.Defaults) > 0, "defaults")
+	only("select multiselect", len(s.Choices) > 0, "choices")
+	only("select", s.AllowCustomEntry, "allow_custom_entry")
+	only("select", s.Searchable, "searchable")
+	only("select", s.Search != "", "search")
+	only("multiselect", s.MinSelections != 0, "min_selections")
+	only("multiselect", s.MaxSelections != 0, "max_selections")
+	only("password", s.Confirmation, "confirmation")
+	only("input", len(s.Validators) > 0, "validate")
+	only("input", s.Pattern != "", "pattern")
+	only("input", s.PatternMessage != "", "pattern_message")
+	only("form", len(s.Fields) > 0, "fields")
+
+	if (s.Type == "select" || s.Type == "multiselect") && len(s.Choices) == 0 {
+		fail("choices", "missing")
+	}
+	for _, d := range s.Defaults {
+		if !slices.Contains(s.Choices, d) {
+			fail("defaults", "%q is not one of the choices", d)
+		}
+	}
+	if s.MinSelections < 0 || s.MaxSelections < 0 || s.MaxSelections > 0 && s.MinSelections > s.MaxSelections {
+		fail("min_selections", "must be between 0 and max_selections")
+	}
+	if s.Type == "form" && len(s.Fields) == 0 {
+		fail("fields", "missing")
+	}
+	errs = append(errs, checkValidators("", s.Validators, s.Pattern)...)
+
+	names := make(map[string]bool)
+	for i, f := range s.Fields {
+		key := fmt.Sprintf("fields[%d]", i)
+		kind, ok := fieldKinds[cmp.Or(f.Kind, "text")]
+		if !ok {
+			fail(key+".kind", "unknown kind %q", f.Kind)
+		}
+		if f.Name == "" {
+			fail(key+".name", "missing")
+		} else if names[f.Name] {
+			fail(key+".name", "duplicate name %q", f.Name)
+		}
+		names[f.Name] = true
+		if kind == FieldCombo && len(f.Choices) == 0 {
+			fail(key+".choices", "missing")
+		} else if kind != FieldCombo && len(f.Choices) > 0 {
+			fail(key+".choices", "not supported by %s fields", cmp.Or(f.Kind, "text"))
+		}
+		if _, isBool := f.Default.(bool); f.Default != nil && isBool != (kind == FieldCheckbox) {
+			fail(key+".default", "invalid default %v for a %s field", f.Default, cmp.Or(f.Kind, "text"))
+		}
+		errs = append(errs, checkValidators(key+".", f.Validators, f.Pattern)...)
+	}
+
+	for i, b := range s.Buttons {
+		key := fmt.Sprintf("buttons[%d]", i)
+		if b.Label == "" {
+			fail(key+".label", "missing")
+		}
+		if _, ok := specRoles[b.Role]; !ok {
+			fail(key+".role", "unknown role %q", b.Role)
+		}
+	}
+
+	if s.Timeout != "" {
+		if d, err := time.ParseDuration(s.Timeout); err != nil || d < 0 {
+			fail("timeout", "invalid duration %q", s.Timeout)
+		}
+	}
+	switch s.TimeoutAction {
+	case "", "cancel", "confirm":
+	default:
+		fail("timeout_action", "unknown action %q", s.TimeoutAction)
+	}
+	switch s.Search {
+	case "", "substring", "fuzzy":
+	default:
+		fail("search", "unknown match mode %q", s.Search)
+	}
+	return errors.Join(errs...)
+}
+
+// checkValidators checks the validator names and the pattern of a spec or of one of its
+// fields, whose keys start with prefix.
+func checkValidators(prefix string, names []string, pattern string) []error {
+	var errs []error
+	for _, name := range names {
+		if _, ok := lookupValidator(name); !ok {
+			errs = append(errs, fmt.Errorf("%svalidate: unknown validator %q", prefix, name))
+		}
+	}
+	if _, err := regexp.Compile(pattern); err != nil {
+		errs = append(errs, fmt.Errorf("%spattern: %w", prefix, err))
+	}
+	return errs
+}
+
+// validator returns a function running the named validators and matching the pattern in
+// order, or nil if there are none. The spec has to be valid.
+func validator(names []string, pattern, message string) func(string) error {
+	var funcs []func(string) error
+	for _, name := range names {
+		v, _ := lookupValidator(name)
+		funcs = append(funcs, v)
+	}
+	if pattern != "" {
+		re := regexp.MustCompile("^(?:" + pattern + ")$")
+		if message == "" {
+			message = fmt.Sprintf("enter a value matching %s", pattern)
+		}
+		funcs = append(funcs, func(s string) error {
+			if !re.MatchString(s) {
+				return errors.New(message)
+			}
+			return nil
+		})
+	}
+	if len(funcs) == 0 {
+		return nil
+	}
+	return func(s string) error {
+		for _, v := range funcs {
+			if err := v(s); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// Run displays the dialog described by s and closes it when ctx is done. The Result and
+// the error are those of the Run function of the dialog type, e.g. RunInput.
+func (s *Spec) Run(ctx context.Context) (Result, error) {
+	if err := s.Validate(); err != nil {
+		return Result{Outcome: Error, Err: err}, err
+	}
+
+	var buttons []Button
+	for _, b := range s.Buttons {
+		buttons = append(buttons, Button{Label: b.Label, Role: specRoles[b.Role], Default: b.Default, Cancel: b.Cancel})
+	}
+	timeout, _ := time.ParseDuration(s.Timeout)
+	action := TimeoutCancel
+	if s.TimeoutAction == "confirm" {
+		action = TimeoutConfirm
+	}
+	searchMode := MatchSubstring
+	if s.Search == "fuzzy" {
+		searchMode = MatchFuzzy
+	}
+
+	switch s.Type {
+	case "input":
+		return RunInput(ctx, InputDialogOptions{
+			Width: s.Width, Height: s.Height,
+			Title: s.Title, Label: s.Label, Description: s.Description,
+			DefaultText: s.Default,
+			Validate:    validator(s.Validators, s.Pattern, s.PatternMessage),
+			Buttons:     buttons,
+			Timeout:     timeout, TimeoutAction: action,
+		})
+	case "password":
+		return RunPassword(ctx, PasswordDialogOptions{
+			Width: s.Width, Height: s.Height,
+			Title: s.Title, Label: s.Label, Description: s.Description,
+			Confirmation: s.Confirmation,
+			Buttons:      buttons,
+			Timeout:      timeout, TimeoutAction: action,
+		})
+	case "select":
+		return RunSelect(ctx, SelectDialogOptions{
+			Width: s.Width, Height: s.Height,
+			Title: s.Title, Label: s.Label, Description: s.Description,
+			Choices:          s.Choices,
+			DefaultSelection: s.Default,
+			AllowCustomEntry: s.AllowCustomEntry,
+			Searchable:       s.Searchable || s.Search != "",
+			SearchMode:       searchMode,
+			Buttons:          buttons,
+			Timeout:          timeout, TimeoutAction: action,
+		})
+	case "multiselect":
+		return RunMultiSelect(ctx, MultiSelectDialogOptions{
+			Width: s.Width, Height: s.Height,
+			Title: s.Title, Label: s.Label, Description: s.Description,
+			Choices:           s.Choices,
+			DefaultSelections: s.Defaults,
+			MinSelections:     s.MinSelections,
+			MaxSelections:     s.MaxSelections,
+			Buttons:           buttons,
+			Timeout:           timeout, TimeoutAction: action,
+		})
+	case "form":
+		fields := make([]FormField, len(s.Fields))
+		for i, f := range s.Fields {
+			fields[i] = FormField{Name: f.Name, Label: cmp.Or(f.Label, f.Name), Kind: fieldKinds[cmp.Or(f.Kind, "text")], Default: f.Default, Choices: f.Choices}
+			if v := validator(f.Validators, f.Pattern, f.PatternMessage); v != nil {
+				fields[i].Validate = func(value any) error { return v(fieldText(value)) }
+			}
+		}
+		return RunForm(ctx, FormDialogOptions{
+			Width: s.Width, Height: s.Height,
+			Title: s.Title, Label: s.Label, Description: s.Description,
+			Fields:  fields,
+			Buttons: buttons,
+			Timeout: timeout, TimeoutAction: action,
+		})
+	default:
+		return RunMessage(ctx, specSeverities[s.Type], MessageDialogOptions{
+			Width: s.Width, Height: s.Height,
+			Title: s.Title, Label: s.Label,
+			Message: s.Message,
+			Buttons: buttons,
+			Timeout: timeout, TimeoutAction: action,
+		})
+	}
+}
+
+// fieldText returns the text of a form field with the given value, for the validators of
+// a spec.
+func fieldText(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case []byte:
+		return string(v)
+	case time.Time:
+		return v.Format(time.DateOnly)
+	default:
+		return fmt.Sprint(v)
+	}
+}
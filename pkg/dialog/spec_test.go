@@ -0,0 +1,241 @@
+package dialog
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLoadSpec(t *testing.T) {
+	want := &Spec{
+		Type:       "input",
+		Title:      "Sign Up",
+		Label:      "Your email address",
+		Default:    "alice@example.com",
+		Validators: []string{"required", "email"},
+		Buttons: []ButtonSpec{
+			{Label: "Later", Role: "negative", Cancel: true},
+			{Label: "Sign up", Role: "affirmative", Default: true},
+		},
+		Timeout: "60s",
+	}
+	tests := map[string]string{
+		"yaml": `
+type: input
+title: Sign Up
+label: Your email address
+default: alice@example.com
+validate: [required, email]
+buttons:
+  - {label: Later, role: negative, cancel: true}
+  - {label: Sign up, role: affirmative, default: true}
+timeout: 60s
+`,
+		"json": `{
+	"type": "input", "title": "Sign Up", "label": "Your email address",
+	"default": "alice@example.com", "validate": ["required", "email"],
+	"buttons": [
+		{"label": "Later", "role": "negative", "cancel": true},
+		{"label": "Sign up", "role": "affirmative", "default": true}
+	],
+	"timeout": "60s"
+}`,
+	}
+	for name, input := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := LoadSpec(strings.NewReader(input))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("got %+v, want %+v", got, want)
+			}
+		})
+	}
+}
+
+func TestLoadSpecErrors(t *testing.T) {
+	tests := []struct {
+		name, input string
+		want        []string
+	}{
+		{"unknown yaml key", "type: input\nlable: typo\n", []string{"lable"}},
+		{"unknown json key", `{"type": "input", "lable": "typo"}`, []string{"lable"}},
+		{"invalid yaml", "type: [input\n", []string{"parsing the dialog spec"}},
+		{"invalid spec", "type: select\nconfirmation: true\n", []string{"choices: missing", "confirmation: not supported by select dialogs"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadSpec(strings.NewReader(tt.input))
+			if err == nil {
+				t.Fatal("no error")
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("error %q doesn't contain %q", err, want)
+				}
+			}
+		})
+	}
+}
+
+func TestSpecValidate(t *testing.T) {
+	tests := []struct {
+		name string
+		spec Spec
+		want []string // one line of the error per problem, nil if valid
+	}{
+		{"input", Spec{Type: "input", Validators: []string{"integer"}, Pattern: "[0-9]+"}, nil},
+		{"search", Spec{Type: "select", Choices: []string{"main", "fix/menu"}, Search: "fuzzy"}, nil},
+		{"message", Spec{Type: "warning", Message: "Disk full", Timeout: "5s", TimeoutAction: "confirm"}, nil},
+		{"form", Spec{Type: "form", Fields: []FieldSpec{
+			{Name: "user"},
+			{Name: "level", Kind: "combo", Choices: []string{"low", "high"}},
+			{Name: "remember", Kind: "checkbox", Default: true},
+		}}, nil},
+		{"missing type", Spec{}, []string{"type: missing"}},
+		{"unknown type", Spec{Type: "calendar"}, []string{`type: unknown dialog type "calendar"`}},
+		{"keys of other types", Spec{Type: "info", Default: "x", Choices: []string{"a"}}, []string{
+			"default: not supported by info dialogs",
+			"choices: not supported by info dialogs",
+		}},
+		{"search of other types", Spec{Type: "input", Search: "fuzzy"}, []string{"search: not supported by input dialogs"}},
+		{"unknown search", Spec{Type: "select", Choices: []string{"a"}, Search: "regexp"}, []string{`search: unknown match mode "regexp"`}},
+		{"selections", Spec{Type: "multiselect", Choices: []string{"a"}, Defaults: []string{"b"}, MinSelections: 2, MaxSelections: 1}, []string{
+			`defaults: "b" is not one of the choices`,
+			"min_selections: must be between 0 and max_selections",
+		}},
+		{"validators", Spec{Type: "input", Validators: []string{"nope"}, Pattern: "("}, []string{
+			`validate: unknown validator "nope"`,
+			"pattern: error parsing regexp",
+		}},
+		{"fields", Spec{Type: "form", Fields: []FieldSpec{
+			{Name: "a", Kind: "slider"},
+			{Name: "a", Kind: "combo"},
+			{Kind: "text", Choices: []string{"x"}},
+			{Name: "b", Kind: "checkbox", Default: "yes"},
+		}}, []string{
+			`fields[0].kind: unknown kind "slider"`,
+			`fields[1].name: duplicate name "a"`,
+			"fields[1].choices: missing",
+			"fields[2].name: missing",
+			"fields[2].choices: not supported by text fields",
+			"fields[3].default: invalid default yes for a checkbox field",
+		}},
+		{"buttons and timeout", Spec{Type: "question", Buttons: []ButtonSpec{{Role: "maybe"}}, Timeout: "soon", TimeoutAction: "wait"}, []string{
+			"buttons[0].label: missing",
+			`buttons[0].role: unknown role "maybe"`,
+			`timeout: invalid duration "soon"`,
+			`timeout_action: unknown action "wait"`,
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.spec.Validate()
+			if tt.want == nil {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("no error")
+			}
+			got := strings.Split(err.Error(), "\n")
+			if len(got) != len(tt.want) {
+				t.Fatalf("got errors %q, want %q", got, tt.want)
+			}
+			for i, want := range tt.want {
+				if !strings.HasPrefix(got[i], want) {
+					t.Errorf("error %d = %q, want %q", i, got[i], want)
+				}
+			}
+		})
+	}
+}
+
+func TestSpecRunInvalid(t *testing.T) {
+	res, err := (&Spec{Type: "select"}).Run(context.Background())
+	if err == nil || res.Outcome != Error || res.Err != err {
+		t.Errorf("got %v %v, want the validation error", res.Outcome, err)
+	}
+}
+
+func TestValidators(t *testing.T) {
+	RegisterValidator("lowercase", func(s string) error {
+		if s != strings.ToLower(s) {
+			return errors.New("use lowercase letters")
+		}
+		return nil
+	})
+	tests := []struct {
+		names            []string
+		pattern, message string
+		text             string
+		want             string // error, or "" if valid
+	}{
+		{[]string{"required"}, "", "", " ", "enter a value"},
+		{[]string{"number"}, "", "", "", ""},
+		{[]string{"number"}, "", "", "1.5", ""},
+		{[]string{"number"}, "", "", "one", "enter a number"},
+		{[]string{"integer"}, "", "", "1.5", "enter a whole number"},
+		{[]string{"email"}, "", "", "alice@example.com", ""},
+		{[]string{"email"}, "", "", "alice@localhost", "enter an email address"},
+		{[]string{"lowercase"}, "", "", "Alice", "use lowercase letters"},
+		// The validators run in order, and the pattern last.
+		{[]string{"required", "lowercase"}, "[a-z]{3}", "", "", "enter a value"},
+		{[]string{"required", "lowercase"}, "[a-z]{3}", "", "alice", "enter a value matching [a-z]{3}"},
+		{nil, "[a-z]{3}", "three letters", "ali", ""},
+		{nil, "[a-z]{3}", "three letters", "xalix", "three letters"},
+	}
+	for _, tt := range tests {
+		v := validator(tt.names, tt.pattern, tt.message)
+		err := v(tt.text)
+		if got := fmt.Sprint(err); tt.want == "" && err != nil || tt.want != "" && got != tt.want {
+			t.Errorf("%v %q: validating %q = %v, want %q", tt.names, tt.pattern, tt.text, err, tt.want)
+		}
+	}
+	if v := validator(nil, "", ""); v != nil {
+		t.Error("validator without validators isn't nil")
+	}
+}
+
+func TestFieldText(t *testing.T) {
+	tests := []struct {
+		value any
+		want  string
+	}{
+		{nil, ""},
+		{"text", "text"},
+		{[]byte("secret"), "secret"},
+		{42.5, "42.5"},
+		{time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC), "2026-10-16"},
+	}
+	for _, tt := range tests {
+		if got := fieldText(tt.value); got != tt.want {
+			t.Errorf("fieldText(%v) = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestSpecSchema(t *testing.T) {
+	var schema struct {
+		Properties map[string]any `json:"properties"`
+	}
+	if err := json.Unmarshal([]byte(SpecSchema), &schema); err != nil {
+		t.Fatal(err)
+	}
+	// Every key of a spec is described by the schema.
+	typ := reflect.TypeFor[Spec]()
+	for i := range typ.NumField() {
+		key, _, _ := strings.Cut(typ.Field(i).Tag.Get("json"), ",")
+		if _, ok := schema.Properties[key]; !ok {
+			t.Errorf("schema lacks the key %q", key)
+		}
+	}
+}
@@ -0,0 +1,187 @@
+package dialogrpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"strconv"
+	"sync"
+
+	"github.com/gesellix/gioui-dialog/pkg/dialog"
+)
+
+// ErrClientClosed is returned by the calls of a Client after its connection was closed.
+var ErrClientClosed = errors.New("dialogrpc: client closed")
+
+// Client calls the methods of a Server. Its methods may be called from any goroutine,
+// and calls run concurrently.
+type Client struct {
+	conn io.ReadWriteCloser
+
+	writeMu sync.Mutex
+
+	mu      sync.Mutex
+	lastID  int64
+	pending map[int64]chan response
+	err     error // set once the connection failed
+}
+
+// NewClient returns a Client talking to a Server over conn, e.g. one end of a net.Pipe
+// or the stdin and stdout of a `gioui-dialog serve` process. Close closes conn.
+func NewClient(conn io.ReadWriteCloser) *Client {
+	c := &Client{conn: conn, pending: make(map[int64]chan response)}
+	go c.read()
+	return c
+}
+
+// Dial connects to a Server listening on the Unix socket at path, e.g. one started with
+// `gioui-dialog serve --socket path`.
+func Dial(ctx context.Context, path string) (*Client, error) {
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "unix", path)
+	if err != nil {
+		return nil, err
+	}
+	return NewClient(conn), nil
+}
+
+// Close closes the connection. Pending calls return ErrClientClosed.
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+// read dispatches the responses to the pending calls until the connection fails.
+func (c *Client) read() {
+	dec := json.NewDecoder(c.conn)
+	for {
+		var res response
+		if err := dec.Decode(&res); err != nil {
+			c.mu.Lock()
+			defer c.mu.Unlock()
+			c.err = ErrClientClosed
+			for id, ch := range c.pending {
+				close(ch)
+				delete(c.pending, id)
+			}
+			return
+		}
+		id, err := strconv.ParseInt(string(res.ID), 10, 64)
+		if err != nil {
+			continue
+		}
+		c.mu.Lock()
+		ch, ok := c.pending[id]
+		delete(c.pending, id)
+		c.mu.Unlock()
+		if ok {
+			ch <- res
+		}
+	}
+}
+
+// Call calls method with params and decodes its result into result, unless it is nil.
+// Errors of the server are returned as *Error. If ctx is done first, Call returns
+// ctx.Err(), but the server still handles the request.
+func (c *Client) Call(ctx context.Context, method string, params, result any) error {
+	rawParams, err := json.Marshal(params)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	if c.err != nil {
+		c.mu.Unlock()
+		return c.err
+	}
+	c.lastID++
+	id := c.lastID
+	ch := make(chan response, 1)
+	c.pending[id] = ch
+	c.mu.Unlock()
+	defer func() {
+		c.mu.Lock()
+		delete(c.pending, id)
+		c.mu.Unlock()
+	}()
+
+	c.writeMu.Lock()
+	err = json.NewEncoder(c.conn).Encode(request{
+		JSONRPC: "2.0",
+		ID:      json.RawMessage(strconv.FormatInt(id, 10)),
+		Method:  method,
+		Params:  rawParams,
+	})
+	c.writeMu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case res, ok := <-ch:
+		if !ok {
+			return ErrClientClosed
+		}
+		if res.Error != nil {
+			return res.Error
+		}
+		if result == nil {
+			return nil
+		}
+		return json.Unmarshal(res.Result, result)
+	}
+}
+
+// Prompt shows the dialog described by spec, and returns its Result once it was closed.
+// The error is only set if the dialog couldn't be shown, e.g. because spec is invalid;
+// use Result.Err for the outcome.
+func (c *Client) Prompt(ctx context.Context, spec dialog.Spec) (Result, error) {
+	method := "prompt." + spec.Type
+	spec.Type = ""
+	var res Result
+	err := c.Call(ctx, method, spec, &res)
+	return res, err
+}
+
+// StartProgress shows a progress dialog and returns immediately.
+func (c *Client) StartProgress(ctx context.Context, params ProgressParams) (*RemoteProgress, error) {
+	var started ProgressStarted
+	if err := c.Call(ctx, "progress.start", params, &started); err != nil {
+		return nil, err
+	}
+	return &RemoteProgress{c: c, id: started.ID}, nil
+}
+
+// RemoteProgress controls a progress dialog shown by a Server. Once the dialog was
+// closed, e.g. because the user pressed Cancel, its methods return an *Error with
+// the code CodeProgressClosed, except for Wait.
+type RemoteProgress struct {
+	c  *Client
+	id int64
+}
+
+// Update applies the fields of u that are set; its ID is ignored.
+func (p *RemoteProgress) Update(ctx context.Context, u ProgressUpdate) error {
+	u.ID = p.id
+	return p.c.Call(ctx, "progress.update", u, nil)
+}
+
+// Complete marks the work as done, see dialog.Progress.Complete.
+func (p *RemoteProgress) Complete(ctx context.Context) error {
+	return p.c.Call(ctx, "progress.complete", ProgressRef{ID: p.id}, nil)
+}
+
+// Close completes the work and closes the dialog.
+func (p *RemoteProgress) Close(ctx context.Context) error {
+	return p.c.Call(ctx, "progress.close", ProgressRef{ID: p.id}, nil)
+}
+
+// Wait waits until the dialog was closed and returns its Result.
+func (p *RemoteProgress) Wait(ctx context.Context) (Result, error) {
+	var res Result
+	err := p.c.Call(ctx, "progress.wait", ProgressRef{ID: p.id}, &res)
+	return res, err
+}
@@ -0,0 +1,137 @@
+// Package dialogrpc serves dialogs over JSON-RPC 2.0, so that a single long-running
+// process can show the dialogs of many requests, and provides a client for it.
+//
+// Messages are JSON values separated by newlines. The methods are:
+//
+//   - prompt.input, prompt.password, prompt.select, prompt.multiselect, prompt.form,
+//     prompt.info, prompt.warning, prompt.error and prompt.question show a dialog and
+//     return its Result once it was closed. Their params are a dialog.Spec without type.
+//   - progress.start shows a progress dialog described by ProgressParams, and returns
+//     its ProgressStarted right away.
+//   - progress.update applies a ProgressUpdate to a progress dialog.
+//   - progress.complete and progress.close complete the work of a progress dialog, and
+//     also close it, respectively. Their params are a ProgressRef.
+//   - progress.wait returns the Result of a progress dialog once it was closed. Its
+//     params are a ProgressRef. The ID is released with the result, so it can only be
+//     waited for once.
+//
+// Requests are handled concurrently, so several dialogs can be open at the same time,
+// and each response is written as soon as it is ready.
+package dialogrpc
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/gesellix/gioui-dialog/pkg/dialog"
+)
+
+// Error codes of the responses. The ones from -32700 to -32603 are defined by JSON-RPC 2.0.
+const (
+	CodeParseError     = -32700
+	CodeInvalidRequest = -32600
+	CodeMethodNotFound = -32601
+	CodeInvalidParams  = -32602
+	CodeInternalError  = -32603
+	// CodeUnknownProgress means that no progress dialog has the given ID, or that its
+	// result was already returned by progress.wait.
+	CodeUnknownProgress = -32000
+	// CodeProgressClosed means that the progress dialog was already closed, e.g. because
+	// the user pressed Cancel. Its Result is returned by progress.wait.
+	CodeProgressClosed = -32001
+)
+
+// Error is the error of a response.
+type Error struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s (code %d)", e.Message, e.Code)
+}
+
+// request is a request or notification, which has no ID.
+type request struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+// response is the response to a request. Either Result or Error is set.
+type response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *Error          `json:"error,omitempty"`
+}
+
+// Result is the result of a prompt method and of progress.wait.
+type Result struct {
+	// Outcome is confirmed, canceled, dismissed, timeout or error.
+	Outcome string `json:"outcome"`
+	// Value is the entered text or password, or the selected choice.
+	Value string `json:"value,omitempty"`
+	// Values are the checked choices of a multiselect dialog.
+	Values []string `json:"values,omitempty"`
+	// Fields are the values of the fields of a form, by name. Passwords are strings, and
+	// dates are strings like 2006-01-02.
+	Fields map[string]any `json:"fields,omitempty"`
+	// Button is the label of the button that closed the dialog.
+	Button string `json:"button,omitempty"`
+	// Error is the cause of the error outcome.
+	Error string `json:"error,omitempty"`
+}
+
+// Err returns nil if the dialog was confirmed, and the error the dialog package returns
+// for the outcome otherwise, e.g. dialog.ErrCanceled.
+func (r Result) Err() error {
+	switch r.Outcome {
+	case "confirmed":
+		return nil
+	case "canceled":
+		return dialog.ErrCanceled
+	case "dismissed":
+		return dialog.ErrDismissed
+	case "timeout":
+		return dialog.ErrTimeout
+	default:
+		return errors.New(r.Error)
+	}
+}
+
+// ProgressParams are the params of progress.start.
+type ProgressParams struct {
+	Width       float32 `json:"width,omitempty"`
+	Height      float32 `json:"height,omitempty"`
+	Title       string  `json:"title,omitempty"`
+	Label       string  `json:"label,omitempty"`
+	Description string  `json:"description,omitempty"`
+	Text        string  `json:"text,omitempty"`
+	Percent     float64 `json:"percent,omitempty"`
+	Pulsate     bool    `json:"pulsate,omitempty"`
+	AutoClose   bool    `json:"auto_close,omitempty"`
+}
+
+// ProgressStarted is the result of progress.start.
+type ProgressStarted struct {
+	// ID identifies the progress dialog in the other progress methods of the same
+	// connection.
+	ID int64 `json:"id"`
+}
+
+// ProgressRef is the params of the progress methods that only need the dialog.
+type ProgressRef struct {
+	ID int64 `json:"id"`
+}
+
+// ProgressUpdate is the params of progress.update. Only the fields that are set are
+// applied.
+type ProgressUpdate struct {
+	ID      int64    `json:"id"`
+	Percent *float64 `json:"percent,omitempty"`
+	Text    *string  `json:"text,omitempty"`
+	Pulsate *bool    `json:"pulsate,omitempty"`
+}
@@ -0,0 +1,423 @@
+package dialogrpc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gesellix/gioui-dialog/pkg/dialog"
+)
+
+// Backend shows the dialogs requested from a Server.
+type Backend interface {
+	// Run shows the dialog described by a valid spec, like dialog.Spec.Run.
+	Run(ctx context.Context, spec *dialog.Spec) (dialog.Result, error)
+	// StartProgress shows a progress dialog, like dialog.StartProgress, which closes
+	// when ctx is done.
+	StartProgress(ctx context.Context, opts dialog.ProgressDialogOptions) Progress
+}
+
+// Progress controls a progress dialog shown by a Backend. *dialog.Progress implements it.
+type Progress interface {
+	SetPercent(percent float64)
+	SetText(text string)
+	SetPulsating(pulsating bool)
+	Complete()
+	Close()
+	Done() <-chan struct{}
+	Wait() (dialog.Result, error)
+}
+
+// DialogBackend is the Backend showing the dialogs of the dialog package.
+type DialogBackend struct{}
+
+func (DialogBackend) Run(ctx context.Context, spec *dialog.Spec) (dialog.Result, error) {
+	return spec.Run(ctx)
+}
+
+// StartProgress drops the context returned by dialog.StartProgress. The work is done by
+// the client, which learns that the dialog was closed from progress.wait, and the server
+// watches Progress.Done.
+func (DialogBackend) StartProgress(ctx context.Context, opts dialog.ProgressDialogOptions) Progress {
+	p, _ := dialog.StartProgress(ctx, opts)
+	return p
+}
+
+// promptTypes are the dialog types of the prompt methods.
+var promptTypes = []string{"input", "password", "select", "multiselect", "form", "info", "warning", "error", "question"}
+
+// Server serves the dialogs of a Backend to JSON-RPC 2.0 clients.
+type Server struct {
+	backend Backend
+}
+
+// NewServer returns a Server showing the dialogs of backend, e.g. DialogBackend.
+func NewServer(backend Backend) *Server {
+	return &Server{backend: backend}
+}
+
+// Serve accepts connections on l and serves each of them like ServeConn, until ctx is
+// done or l fails. It closes l.
+func (s *Server) Serve(ctx context.Context, l net.Listener) error {
+	stop := context.AfterFunc(ctx, func() { l.Close() })
+	defer stop()
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return err
+		}
+		wg.Go(func() {
+			defer conn.Close()
+			s.ServeConn(ctx, conn, conn)
+		})
+	}
+}
+
+// ServeConn handles the requests read from r and writes the responses to w. It returns
+// nil at the end of r, once the pending requests were answered, which lets clients close
+// their side of a connection right after their last request. When ctx is done or r
+// fails, the open dialogs are closed, and ServeConn returns the error.
+func (s *Server) ServeConn(ctx context.Context, r io.Reader, w io.Writer) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	c := &conn{backend: s.backend, ctx: ctx, w: w, progress: make(map[int64]*runningProgress), results: make(map[int64]Result)}
+	defer c.wg.Wait()
+
+	msgs := make(chan json.RawMessage)
+	errc := make(chan error, 1)
+	go func() {
+		dec := json.NewDecoder(r)
+		for {
+			var msg json.RawMessage
+			if err := dec.Decode(&msg); err != nil {
+				errc <- err
+				return
+			}
+			select {
+			case msgs <- msg:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case err := <-errc:
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			var syntaxErr *json.SyntaxError
+			if errors.As(err, &syntaxErr) {
+				// The stream can't be resynchronized after invalid JSON.
+				c.write(response{JSONRPC: "2.0", ID: json.RawMessage("null"), Error: &Error{Code: CodeParseError, Message: err.Error()}})
+			}
+			cancel()
+			return err
+		case msg := <-msgs:
+			c.wg.Go(func() { c.handleMessage(msg) })
+		}
+	}
+}
+
+// conn holds the state of a connection served by ServeConn.
+type conn struct {
+	backend Backend
+	ctx     context.Context
+	wg      sync.WaitGroup
+
+	writeMu sync.Mutex
+	w       io.Writer
+
+	mu       sync.Mutex
+	progress map[int64]*runningProgress
+	results  map[int64]Result // of the closed progress dialogs, until progress.wait
+	lastID   int64
+}
+
+// runningProgress is a progress dialog that wasn't closed yet.
+type runningProgress struct {
+	Progress
+	closed chan struct{} // closed once its result was moved to conn.results
+}
+
+// write writes the responses of a request, or of a batch as an array.
+func (c *conn) write(res any) {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	// Write errors surface as the end of the input, as the client is gone.
+	json.NewEncoder(c.w).Encode(res)
+}
+
+// handleMessage handles a request, a notification or a batch of them, and writes the
+// responses.
+func (c *conn) handleMessage(msg json.RawMessage) {
+	if !bytes.HasPrefix(bytes.TrimSpace(msg), []byte("[")) {
+		if res, ok := c.handle(msg); ok {
+			c.write(res)
+		}
+		return
+	}
+
+	var batch []json.RawMessage
+	if err := json.Unmarshal(msg, &batch); err != nil || len(batch) == 0 {
+		c.write(response{JSONRPC: "2.0", ID: json.RawMessage("null"), Error: &Error{Code: CodeInvalidRequest, Message: "invalid batch"}})
+		return
+	}
+	responses := make([]*response, len(batch))
+	var wg sync.WaitGroup
+	for i, msg := range batch {
+		wg.Go(func() {
+			if res, ok := c.handle(msg); ok {
+				responses[i] = &res
+			}
+		})
+	}
+	wg.Wait()
+	var out []*response
+	for _, res := range responses {
+		if res != nil {
+			out = append(out, res)
+		}
+	}
+	if len(out) > 0 {
+		c.write(out)
+	}
+}
+
+// handle handles a request and returns its response, or false for a notification.
+func (c *conn) handle(msg json.RawMessage) (response, bool) {
+	var req request
+	if err := json.Unmarshal(msg, &req); err != nil || req.JSONRPC != "2.0" || req.Method == "" {
+		return response{JSONRPC: "2.0", ID: json.RawMessage("null"), Error: &Error{Code: CodeInvalidRequest, Message: "invalid request"}}, true
+	}
+	result, err := c.call(req.Method, req.Params)
+	if req.ID == nil {
+		return response{}, false
+	}
+
+	res := response{JSONRPC: "2.0", ID: req.ID}
+	if err == nil {
+		res.Result, err = json.Marshal(result)
+	}
+	if err != nil {
+		rpcErr, ok := err.(*Error)
+		if !ok {
+			rpcErr = &Error{Code: CodeInternalError, Message: err.Error()}
+		}
+		res.Result, res.Error = nil, rpcErr
+	}
+	return res, true
+}
+
+// call calls the method with the given params and returns its result.
+func (c *conn) call(method string, params json.RawMessage) (any, error) {
+	if typ, ok := strings.CutPrefix(method, "prompt."); ok && slices.Contains(promptTypes, typ) {
+		var spec dialog.Spec
+		if err := decodeParams(params, &spec); err != nil {
+			return nil, err
+		}
+		if spec.Type != "" && spec.Type != typ {
+			return nil, &Error{Code: CodeInvalidParams, Message: fmt.Sprintf("type %q doesn't match the method %s", spec.Type, method)}
+		}
+		spec.Type = typ
+		if err := spec.Validate(); err != nil {
+			return nil, &Error{Code: CodeInvalidParams, Message: err.Error()}
+		}
+		return newResult(c.backend.Run(c.ctx, &spec)), nil
+	}
+
+	switch method {
+	case "progress.start":
+		var p ProgressParams
+		if err := decodeParams(params, &p); err != nil {
+			return nil, err
+		}
+		progress := c.backend.StartProgress(c.ctx, dialog.ProgressDialogOptions{
+			Width: p.Width, Height: p.Height,
+			Title: p.Title, Label: p.Label, Description: p.Description,
+			Text:      p.Text,
+			Percent:   p.Percent,
+			Pulsate:   p.Pulsate,
+			AutoClose: p.AutoClose,
+		})
+		running := &runningProgress{Progress: progress, closed: make(chan struct{})}
+		c.mu.Lock()
+		c.lastID++
+		id := c.lastID
+		c.progress[id] = running
+		c.mu.Unlock()
+		go c.watchProgress(id, running)
+		return ProgressStarted{ID: id}, nil
+	case "progress.update":
+		var u ProgressUpdate
+		if err := decodeParams(params, &u); err != nil {
+			return nil, err
+		}
+		p, err := c.lookupProgress(u.ID)
+		if err != nil {
+			return nil, err
+		}
+		if u.Pulsate != nil {
+			p.SetPulsating(*u.Pulsate)
+		}
+		if u.Text != nil {
+			p.SetText(*u.Text)
+		}
+		if u.Percent != nil {
+			p.SetPercent(*u.Percent)
+		}
+		return nil, nil
+	case "progress.complete", "progress.close":
+		var ref ProgressRef
+		if err := decodeParams(params, &ref); err != nil {
+			return nil, err
+		}
+		p, err := c.lookupProgress(ref.ID)
+		if err != nil {
+			return nil, err
+		}
+		if method == "progress.complete" {
+			p.Complete()
+		} else {
+			p.Close()
+		}
+		return nil, nil
+	case "progress.wait":
+		var ref ProgressRef
+		if err := decodeParams(params, &ref); err != nil {
+			return nil, err
+		}
+		c.mu.Lock()
+		p, running := c.progress[ref.ID]
+		c.mu.Unlock()
+		if running {
+			select {
+			case <-p.closed:
+			case <-c.ctx.Done():
+				return nil, c.ctx.Err()
+			}
+		}
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		res, ok := c.results[ref.ID]
+		if !ok {
+			return nil, unknownProgress(ref.ID)
+		}
+		delete(c.results, ref.ID)
+		return res, nil
+	default:
+		return nil, &Error{Code: CodeMethodNotFound, Message: fmt.Sprintf("unknown method %q", method)}
+	}
+}
+
+// lookupProgress returns the progress dialog with the given ID, if it wasn't closed yet.
+func (c *conn) lookupProgress(id int64) (Progress, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	p, ok := c.progress[id]
+	if !ok {
+		if _, closed := c.results[id]; closed {
+			return nil, &Error{Code: CodeProgressClosed, Message: fmt.Sprintf("progress dialog %d is closed", id)}
+		}
+		return nil, unknownProgress(id)
+	}
+	select {
+	case <-p.Done():
+		return nil, &Error{Code: CodeProgressClosed, Message: fmt.Sprintf("progress dialog %d is closed", id)}
+	default:
+		return p, nil
+	}
+}
+
+func unknownProgress(id int64) *Error {
+	return &Error{Code: CodeUnknownProgress, Message: fmt.Sprintf("unknown progress dialog %d", id)}
+}
+
+// watchProgress replaces a progress dialog by its result once it was closed, so that
+// the connection doesn't hold on to closed dialogs.
+func (c *conn) watchProgress(id int64, p *runningProgress) {
+	select {
+	case <-p.Done():
+	case <-c.ctx.Done():
+		return
+	}
+	res := newResult(p.Wait())
+	c.mu.Lock()
+	delete(c.progress, id)
+	c.results[id] = res
+	c.mu.Unlock()
+	close(p.closed)
+}
+
+// decodeParams decodes the params of a request into v, rejecting unknown keys.
+func decodeParams(params json.RawMessage, v any) error {
+	if len(params) == 0 {
+		params = json.RawMessage("{}")
+	}
+	dec := json.NewDecoder(bytes.NewReader(params))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return &Error{Code: CodeInvalidParams, Message: err.Error()}
+	}
+	return nil
+}
+
+// newResult returns the Result of a dialog that returned res and err.
+func newResult(res dialog.Result, err error) Result {
+	out := Result{Outcome: outcomeName(err), Values: res.Values, Button: res.Button.Label}
+	if res.Secret != nil {
+		out.Value = string(res.Secret)
+		clear(res.Secret)
+	} else {
+		out.Value = res.Value
+	}
+	if res.Fields != nil {
+		out.Fields = make(map[string]any, len(res.Fields))
+		for name, v := range res.Fields {
+			switch value := v.(type) {
+			case []byte:
+				v = string(value)
+				clear(value)
+			case time.Time:
+				v = value.Format(time.DateOnly)
+			}
+			out.Fields[name] = v
+		}
+	}
+	if out.Outcome == "error" {
+		out.Error = err.Error()
+	}
+	return out
+}
+
+// outcomeName returns the outcome of a Result for the error of a dialog.
+func outcomeName(err error) string {
+	switch {
+	case err == nil:
+		return "confirmed"
+	case errors.Is(err, dialog.ErrCanceled):
+		return "canceled"
+	case errors.Is(err, dialog.ErrDismissed):
+		return "dismissed"
+	case errors.Is(err, dialog.ErrTimeout):
+		return "timeout"
+	default:
+		return "error"
+	}
+}
@@ -0,0 +1,337 @@
+package dialogrpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gesellix/gioui-dialog/pkg/dialog"
+)
+
+// fakeBackend answers the dialogs with run instead of showing them.
+type fakeBackend struct {
+	run func(ctx context.Context, spec *dialog.Spec) (dialog.Result, error)
+
+	mu       sync.Mutex
+	progress []*fakeProgress
+}
+
+func (b *fakeBackend) Run(ctx context.Context, spec *dialog.Spec) (dialog.Result, error) {
+	return b.run(ctx, spec)
+}
+
+func (b *fakeBackend) StartProgress(ctx context.Context, opts dialog.ProgressDialogOptions) Progress {
+	p := &fakeProgress{opts: opts, done: make(chan struct{})}
+	b.mu.Lock()
+	b.progress = append(b.progress, p)
+	b.mu.Unlock()
+	return p
+}
+
+// fakeProgress records the calls of a progress dialog, which closes on Close.
+type fakeProgress struct {
+	opts dialog.ProgressDialogOptions
+	done chan struct{}
+	once sync.Once
+
+	mu        sync.Mutex
+	percent   float64
+	text      string
+	pulsating bool
+	completed bool
+}
+
+func (p *fakeProgress) SetPercent(percent float64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.percent, p.pulsating = percent, false
+}
+
+func (p *fakeProgress) SetText(text string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.text = text
+}
+
+func (p *fakeProgress) SetPulsating(pulsating bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.pulsating = pulsating
+}
+
+func (p *fakeProgress) Complete() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.completed = true
+}
+
+func (p *fakeProgress) Close() {
+	p.once.Do(func() { close(p.done) })
+}
+
+func (p *fakeProgress) Done() <-chan struct{} {
+	return p.done
+}
+
+func (p *fakeProgress) Wait() (dialog.Result, error) {
+	<-p.done
+	return dialog.Result{Outcome: dialog.Confirmed}, nil
+}
+
+// newTestClient serves backend over a pipe and returns a client for it.
+func newTestClient(t *testing.T, backend Backend) *Client {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	server, client := net.Pipe()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		NewServer(backend).ServeConn(ctx, server, server)
+	}()
+	c := NewClient(client)
+	t.Cleanup(func() {
+		cancel()
+		c.Close()
+		server.Close()
+		<-done
+	})
+	return c
+}
+
+func TestPrompt(t *testing.T) {
+	var got *dialog.Spec
+	c := newTestClient(t, &fakeBackend{run: func(ctx context.Context, spec *dialog.Spec) (dialog.Result, error) {
+		got = spec
+		return dialog.Result{Outcome: dialog.Confirmed, Value: "Alice", Button: dialog.Button{Label: "OK"}}, nil
+	}})
+
+	res, err := c.Prompt(context.Background(), dialog.Spec{Type: "input", Label: "Your name?", Validators: []string{"required"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (Result{Outcome: "confirmed", Value: "Alice", Button: "OK"}); !reflect.DeepEqual(res, want) {
+		t.Errorf("result = %+v, want %+v", res, want)
+	}
+	if got.Type != "input" || got.Label != "Your name?" || !reflect.DeepEqual(got.Validators, []string{"required"}) {
+		t.Errorf("spec = %+v", got)
+	}
+}
+
+func TestPromptOutcomes(t *testing.T) {
+	tests := []struct {
+		spec    dialog.Spec
+		res     dialog.Result
+		err     error
+		want    Result
+		wantErr error
+	}{
+		{
+			spec:    dialog.Spec{Type: "question", Message: "Continue?"},
+			res:     dialog.Result{Outcome: dialog.Canceled, Button: dialog.Button{Label: "No"}},
+			err:     dialog.ErrCanceled,
+			want:    Result{Outcome: "canceled", Button: "No"},
+			wantErr: dialog.ErrCanceled,
+		},
+		{
+			spec:    dialog.Spec{Type: "select", Choices: []string{"a", "b"}, Default: "b"},
+			res:     dialog.Result{Outcome: dialog.TimedOut, Value: "b"},
+			err:     dialog.ErrTimeout,
+			want:    Result{Outcome: "timeout", Value: "b"},
+			wantErr: dialog.ErrTimeout,
+		},
+		{
+			spec: dialog.Spec{Type: "password"},
+			res:  dialog.Result{Outcome: dialog.Confirmed, Secret: []byte("s3cret")},
+			want: Result{Outcome: "confirmed", Value: "s3cret"},
+		},
+		{
+			spec: dialog.Spec{Type: "form", Fields: []dialog.FieldSpec{{Name: "born", Kind: "date"}, {Name: "pin", Kind: "password"}}},
+			res: dialog.Result{Outcome: dialog.Confirmed, Fields: map[string]any{
+				"born": time.Date(1990, 5, 17, 0, 0, 0, 0, time.UTC),
+				"pin":  []byte("1234"),
+			}},
+			want: Result{Outcome: "confirmed", Fields: map[string]any{"born": "1990-05-17", "pin": "1234"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.spec.Type, func(t *testing.T) {
+			c := newTestClient(t, &fakeBackend{run: func(ctx context.Context, spec *dialog.Spec) (dialog.Result, error) {
+				return tt.res, tt.err
+			}})
+			res, err := c.Prompt(context.Background(), tt.spec)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(res, tt.want) {
+				t.Errorf("result = %+v, want %+v", res, tt.want)
+			}
+			if err := res.Err(); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Err() = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCallErrors(t *testing.T) {
+	c := newTestClient(t, &fakeBackend{run: func(ctx context.Context, spec *dialog.Spec) (dialog.Result, error) {
+		t.Errorf("dialog %q shown", spec.Type)
+		return dialog.Result{}, nil
+	}})
+
+	tests := []struct {
+		method string
+		params any
+		code   int
+	}{
+		{"prompt.select", map[string]any{"label": "Pick one"}, CodeInvalidParams},
+		{"prompt.input", map[string]any{"lable": "typo"}, CodeInvalidParams},
+		{"prompt.input", map[string]any{"type": "select"}, CodeInvalidParams},
+		{"prompt.calendar", nil, CodeMethodNotFound},
+		{"progress.update", ProgressUpdate{ID: 42}, CodeUnknownProgress},
+	}
+	for _, tt := range tests {
+		err := c.Call(context.Background(), tt.method, tt.params, nil)
+		var rpcErr *Error
+		if !errors.As(err, &rpcErr) || rpcErr.Code != tt.code {
+			t.Errorf("%s(%v) = %v, want code %d", tt.method, tt.params, err, tt.code)
+		}
+	}
+}
+
+func TestConcurrentPrompts(t *testing.T) {
+	release := make(chan struct{})
+	c := newTestClient(t, &fakeBackend{run: func(ctx context.Context, spec *dialog.Spec) (dialog.Result, error) {
+		if spec.Label == "slow" {
+			<-release
+		}
+		return dialog.Result{Outcome: dialog.Confirmed, Value: spec.Label}, nil
+	}})
+
+	slow := make(chan Result)
+	go func() {
+		res, _ := c.Prompt(context.Background(), dialog.Spec{Type: "input", Label: "slow"})
+		slow <- res
+	}()
+	res, err := c.Prompt(context.Background(), dialog.Spec{Type: "input", Label: "fast"})
+	if err != nil || res.Value != "fast" {
+		t.Fatalf("fast prompt = %+v, %v, want it to return before the slow one", res, err)
+	}
+	close(release)
+	if res := <-slow; res.Value != "slow" {
+		t.Errorf("slow prompt = %+v", res)
+	}
+}
+
+func TestProgress(t *testing.T) {
+	backend := &fakeBackend{}
+	c := newTestClient(t, backend)
+	ctx := context.Background()
+
+	p, err := c.StartProgress(ctx, ProgressParams{Title: "Backup", Pulsate: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	percent, text := 40.0, "Copying"
+	if err := p.Update(ctx, ProgressUpdate{Percent: &percent, Text: &text}); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Complete(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	fake := backend.progress[0]
+	fake.mu.Lock()
+	if fake.opts.Title != "Backup" || !fake.opts.Pulsate || fake.percent != 40 || fake.text != "Copying" || fake.pulsating || !fake.completed {
+		t.Errorf("progress = %+v", fake)
+	}
+	fake.mu.Unlock()
+
+	// The user closes the dialog.
+	fake.Close()
+	var rpcErr *Error
+	if err := p.Update(ctx, ProgressUpdate{Text: &text}); !errors.As(err, &rpcErr) || rpcErr.Code != CodeProgressClosed {
+		t.Errorf("Update after closing = %v, want code %d", err, CodeProgressClosed)
+	}
+	res, err := p.Wait(ctx)
+	if err != nil || res.Outcome != "confirmed" {
+		t.Errorf("Wait() = %+v, %v", res, err)
+	}
+	// The ID is released with the result.
+	if _, err := p.Wait(ctx); !errors.As(err, &rpcErr) || rpcErr.Code != CodeUnknownProgress {
+		t.Errorf("second Wait() = %v, want code %d", err, CodeUnknownProgress)
+	}
+}
+
+func TestProgressWaitBeforeClose(t *testing.T) {
+	backend := &fakeBackend{}
+	c := newTestClient(t, backend)
+	ctx := context.Background()
+
+	p, err := c.StartProgress(ctx, ProgressParams{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	waited := make(chan error)
+	go func() {
+		_, err := p.Wait(ctx)
+		waited <- err
+	}()
+	select {
+	case err := <-waited:
+		t.Fatalf("Wait() returned %v before the dialog was closed", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+	if err := p.Close(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := <-waited; err != nil {
+		t.Errorf("Wait() = %v", err)
+	}
+}
+
+func TestServeConn(t *testing.T) {
+	backend := &fakeBackend{run: func(ctx context.Context, spec *dialog.Spec) (dialog.Result, error) {
+		return dialog.Result{Outcome: dialog.Confirmed}, nil
+	}}
+	input := strings.Join([]string{
+		// A notification, which has no response.
+		`{"jsonrpc":"2.0","method":"prompt.info","params":{"message":"Hello"}}`,
+		`[{"jsonrpc":"2.0","id":1,"method":"prompt.info","params":{"message":"Hello"}},{"jsonrpc":"2.0","id":"two","method":"nope"}]`,
+	}, "\n")
+	var out strings.Builder
+	if err := NewServer(backend).ServeConn(context.Background(), strings.NewReader(input), &out); err != nil {
+		t.Fatal(err)
+	}
+
+	var batch []response
+	if err := json.Unmarshal([]byte(out.String()), &batch); err != nil {
+		t.Fatalf("output %q: %v", out.String(), err)
+	}
+	if len(batch) != 2 {
+		t.Fatalf("got %d responses, want 2", len(batch))
+	}
+	if string(batch[0].ID) != "1" || string(batch[0].Result) != `{"outcome":"confirmed"}` {
+		t.Errorf("first response = %+v", batch[0])
+	}
+	if string(batch[1].ID) != `"two"` || batch[1].Error == nil || batch[1].Error.Code != CodeMethodNotFound {
+		t.Errorf("second response = %+v", batch[1])
+	}
+}
+
+func TestServeConnParseError(t *testing.T) {
+	var out strings.Builder
+	err := NewServer(&fakeBackend{}).ServeConn(context.Background(), strings.NewReader(`{"jsonrpc":`+"\n}"), &out)
+	if err == nil {
+		t.Error("ServeConn succeeded on invalid JSON")
+	}
+	var res response
+	if err := json.Unmarshal([]byte(out.String()), &res); err != nil || res.Error == nil || res.Error.Code != CodeParseError {
+		t.Errorf("response = %q, want a parse error", out.String())
+	}
+}